@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -10,8 +11,33 @@ import (
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/lazyimports"
 )
 
+const (
+	// lazyImportsPollInterval is how often --watch checks the known files'
+	// modification times for saves.
+	lazyImportsPollInterval = 500 * time.Millisecond
+
+	// lazyImportsDebounce is how long files must be quiet before --watch
+	// re-checks them, so a burst of saves across several polls triggers a
+	// single check.
+	lazyImportsDebounce = 3 * lazyImportsPollInterval
+
+	// lazyImportsRescanInterval is how often --watch walks the tree again to
+	// pick up new files; walking the whole backend every poll is too slow.
+	lazyImportsRescanInterval = 5 * time.Second
+)
+
+// CheckLazyImportsOptions holds options for the check-lazy-imports command.
+type CheckLazyImportsOptions struct {
+	Watch          bool
+	Baseline       bool
+	UpdateBaseline bool
+	Fix            bool
+}
+
 // NewCheckLazyImportsCommand creates the check-lazy-imports command.
 func NewCheckLazyImportsCommand() *cobra.Command {
+	opts := &CheckLazyImportsOptions{}
+
 	cmd := &cobra.Command{
 		Use:   "check-lazy-imports [paths...]",
 		Short: "Check that specified modules are only lazily imported",
@@ -19,7 +45,9 @@ func NewCheckLazyImportsCommand() *cobra.Command {
 
 Certain modules (like openai, tiktoken, transformers, etc.) should only be
 imported inside functions, not at the module level. This command scans Python
-files to detect violations of this rule.
+files to detect violations of this rule. Imports in module-level blocks that
+run at import time (try, with, class bodies, ...) count as module level;
+imports under "if TYPE_CHECKING:" or "if __name__ == "__main__":" don't.
 
 Optionally provide files or directories to limit the check; if none are
 provided, all backend Python files are scanned.
@@ -27,42 +55,246 @@ provided, all backend Python files are scanned.
 Examples:
   ods check-lazy-imports                     # Check all backend Python files
   ods check-lazy-imports onyx/llm/           # Check only files in onyx/llm/
-  ods check-lazy-imports onyx/chat/chat.py   # Check a specific file`,
+  ods check-lazy-imports onyx/chat/chat.py   # Check a specific file
+  ods check-lazy-imports --watch             # Re-check files as they are saved
+  ods check-lazy-imports --baseline          # Fail only on violations not in the baseline
+  ods check-lazy-imports --update-baseline   # Record current violations as the baseline
+  ods check-lazy-imports --fix onyx/llm/     # Rewrite fixable eager imports
+
+With --watch, the check runs once and then keeps running, re-checking each
+Python file as it is saved and printing any new violations. Ignored
+directories (e.g. virtualenvs) are not watched. Stop with Ctrl+C.
+
+With --baseline, violations listed in backend/` + lazyimports.BaselineFileName + ` are
+reported as known and only new ones fail the check. Entries match on file and
+import statement, so moving an import within a file doesn't break the baseline.
+--update-baseline rewrites the file from a full scan; commit the result so the
+change is reviewed.
+
+With --fix, eager imports are rewritten where it can be done mechanically: an
+import used only inside functions is moved into each function that uses it,
+and one also used in annotations of a file with "from __future__ import
+annotations" is moved under "if TYPE_CHECKING:" as well. Imports used at
+module level, multi-line imports, and unused imports are reported for manual
+fixing, and the check fails if any remain. Review the diff and run the
+formatter afterwards.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			runCheckLazyImports(args)
+			if opts.UpdateBaseline {
+				if len(args) > 0 {
+					log.Fatal("--update-baseline scans all backend files and does not accept paths")
+				}
+				runUpdateLazyImportsBaseline()
+				return
+			}
+			if opts.Watch {
+				runCheckLazyImportsWatch(args)
+				return
+			}
+			runCheckLazyImports(args, opts)
 		},
 	}
 
+	cmd.Flags().BoolVar(&opts.Watch, "watch", false, "Keep running and re-check Python files when they are saved")
+	cmd.Flags().BoolVar(&opts.Baseline, "baseline", false, "Fail only on violations not listed in the committed baseline")
+	cmd.Flags().BoolVar(&opts.UpdateBaseline, "update-baseline", false, "Regenerate the baseline from the current violations")
+	cmd.Flags().BoolVar(&opts.Fix, "fix", false, "Rewrite eager imports into function-local or TYPE_CHECKING imports where possible")
+	cmd.MarkFlagsMutuallyExclusive("baseline", "update-baseline")
+	cmd.MarkFlagsMutuallyExclusive("watch", "update-baseline")
+	cmd.MarkFlagsMutuallyExclusive("fix", "watch")
+	cmd.MarkFlagsMutuallyExclusive("fix", "update-baseline")
+
 	return cmd
 }
 
-func runCheckLazyImports(providedPaths []string) {
+func runCheckLazyImports(providedPaths []string, opts *CheckLazyImportsOptions) {
 	modules := lazyimports.DefaultLazyImportModules()
 
-	violations, allViolatedModules, err := lazyimports.CheckLazyImports(modules, providedPaths)
+	violations, summary, err := lazyimports.CheckLazyImports(modules, providedPaths)
 	if err != nil {
 		log.Fatalf("Error checking lazy imports: %v", err)
 	}
 
-	if len(violations) > 0 {
+	if opts.Baseline {
+		baseline := loadLazyImportsBaseline()
+		var known int
+		violations, known = baseline.FilterNew(violations)
+		if known > 0 {
+			log.Infof("Ignoring %d known violation(s) listed in %s", known, lazyimports.BaselineFileName)
+		}
+		summary.ViolatedModules = map[string]struct{}{}
 		for _, v := range violations {
-			log.Errorf("\n❌ Eager import violations found in %s:", v.RelPath)
-
-			for _, line := range v.ViolationLines {
-				log.Errorf("  Line %d: %s", line.LineNum, line.Content)
+			for m := range v.ViolatedModules {
+				summary.ViolatedModules[m] = struct{}{}
 			}
+		}
+	}
 
-			if len(v.ViolatedModules) > 0 {
-				log.Errorf("  💡 You must lazy import %s within functions when needed",
-					lazyimports.FormatViolatedModules(v.ViolatedModules))
+	if opts.Fix && len(violations) > 0 {
+		violations = fixLazyImports(violations)
+		summary.ViolatedModules = map[string]struct{}{}
+		for _, v := range violations {
+			for m := range v.ViolatedModules {
+				summary.ViolatedModules[m] = struct{}{}
 			}
 		}
+	}
 
-		violatedModulesStr := lazyimports.FormatViolatedModules(allViolatedModules)
+	if len(violations) > 0 {
+		printLazyImportViolations(violations)
+
+		violatedModulesStr := lazyimports.FormatViolatedModules(summary.ViolatedModules)
 		fmt.Fprintf(os.Stderr, "\nFound eager imports of %s. You must import them only when needed.\n", violatedModulesStr)
 		os.Exit(1)
 	}
 
-	log.Info("✅ All lazy modules are properly imported!")
+	log.Infof("✅ All lazy modules are properly imported! (%d files checked)", summary.FilesScanned)
+}
+
+// fixLazyImports rewrites the fixable violations and returns those left to
+// fix by hand, each with the reason it was skipped logged.
+func fixLazyImports(violations []lazyimports.FileViolation) []lazyimports.FileViolation {
+	results, err := lazyimports.FixLazyImports(violations)
+	if err != nil {
+		log.Fatalf("Error fixing lazy imports: %v", err)
+	}
+
+	var remaining []lazyimports.FileViolation
+	var fixed, files int
+	for _, r := range results {
+		if len(r.Fixed) > 0 {
+			files++
+			fixed += len(r.Fixed)
+			log.Infof("🔧 Fixed %d import(s) in %s", len(r.Fixed), r.RelPath)
+		}
+		if len(r.Skipped) == 0 {
+			continue
+		}
+		left := lazyimports.FileViolation{RelPath: r.RelPath, ViolatedModules: map[string]struct{}{}}
+		for _, s := range r.Skipped {
+			log.Warnf("Cannot fix %s:%d automatically: %s", r.RelPath, s.Line.LineNum, s.Reason)
+			left.ViolationLines = append(left.ViolationLines, s.Line)
+			left.ViolatedModules[s.Line.Module] = struct{}{}
+		}
+		remaining = append(remaining, left)
+	}
+	if fixed > 0 {
+		log.Infof("Fixed %d import(s) in %d file(s); review the changes and run the formatter", fixed, files)
+	}
+	return remaining
+}
+
+// loadLazyImportsBaseline reads the committed baseline, exiting on error.
+func loadLazyImportsBaseline() lazyimports.Baseline {
+	path, err := lazyimports.BaselinePath()
+	if err != nil {
+		log.Fatalf("Failed to locate baseline: %v", err)
+	}
+	baseline, err := lazyimports.LoadBaseline(path)
+	if err != nil {
+		log.Fatalf("Failed to load baseline: %v", err)
+	}
+	return baseline
 }
 
+// runUpdateLazyImportsBaseline records every current violation in the baseline.
+func runUpdateLazyImportsBaseline() {
+	violations, _, err := lazyimports.CheckLazyImports(lazyimports.DefaultLazyImportModules(), nil)
+	if err != nil {
+		log.Fatalf("Error checking lazy imports: %v", err)
+	}
+	path, err := lazyimports.BaselinePath()
+	if err != nil {
+		log.Fatalf("Failed to locate baseline: %v", err)
+	}
+	previous, err := lazyimports.LoadBaseline(path)
+	if err != nil {
+		log.Fatalf("Failed to load baseline: %v", err)
+	}
+	if err := lazyimports.WriteBaseline(path, violations); err != nil {
+		log.Fatalf("Failed to update baseline: %v", err)
+	}
+	updated, err := lazyimports.LoadBaseline(path)
+	if err != nil {
+		log.Fatalf("Failed to load baseline: %v", err)
+	}
+	log.Infof("Wrote %d known violation(s) to %s (previously %d)", updated.Len(), path, previous.Len())
+}
+
+// printLazyImportViolations logs each file's eager imports with a hint.
+func printLazyImportViolations(violations []lazyimports.FileViolation) {
+	for _, v := range violations {
+		log.Errorf("\n❌ Eager import violations found in %s:", v.RelPath)
+
+		for _, line := range v.ViolationLines {
+			log.Errorf("  Line %d: %s", line.LineNum, line.Content)
+		}
+
+		if len(v.ViolatedModules) > 0 {
+			log.Errorf("  💡 You must lazy import %s within functions when needed",
+				lazyimports.FormatViolatedModules(v.ViolatedModules))
+		}
+	}
+}
+
+// runCheckLazyImportsWatch runs a full check, then polls for saved Python
+// files and re-checks just those, until interrupted.
+func runCheckLazyImportsWatch(providedPaths []string) {
+	modules := lazyimports.DefaultLazyImportModules()
+
+	violations, _, err := lazyimports.CheckLazyImports(modules, providedPaths)
+	if err != nil {
+		log.Fatalf("Error checking lazy imports: %v", err)
+	}
+	printLazyImportViolations(violations)
+	if len(violations) == 0 {
+		log.Info("✅ All lazy modules are properly imported!")
+	}
+
+	files, err := lazyimports.WatchedFiles(providedPaths)
+	if err != nil {
+		log.Fatalf("Error listing Python files: %v", err)
+	}
+	mtimes := lazyimports.ModTimes(files)
+	lastScan := time.Now()
+	log.Infof("Watching %d Python files for changes (Ctrl+C to stop)...", len(files))
+
+	var pending []string
+	var lastChange time.Time
+	for {
+		time.Sleep(lazyImportsPollInterval)
+
+		if time.Since(lastScan) >= lazyImportsRescanInterval {
+			if scanned, err := lazyimports.WatchedFiles(providedPaths); err != nil {
+				log.Warnf("Error listing Python files: %v", err)
+			} else {
+				files = scanned
+			}
+			lastScan = time.Now()
+		}
+
+		// Only the known files are stat'ed; each is compared to the previous poll
+		current := lazyimports.ModTimes(files)
+		if changed := lazyimports.ChangedFiles(mtimes, current); len(changed) > 0 {
+			pending = append(pending, changed...)
+			lastChange = time.Now()
+		}
+		mtimes = current
+
+		if len(pending) == 0 || time.Since(lastChange) < lazyImportsDebounce {
+			continue
+		}
+
+		toCheck := dedupeNonEmpty(pending)
+		pending = nil
+		violations, _, err := lazyimports.CheckLazyImports(modules, toCheck)
+		if err != nil {
+			log.Warnf("Error checking lazy imports: %v", err)
+			continue
+		}
+		if len(violations) > 0 {
+			printLazyImportViolations(violations)
+		} else {
+			log.Infof("✅ %d changed file(s) OK", len(toCheck))
+		}
+	}
+}
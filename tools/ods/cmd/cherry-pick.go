@@ -1,33 +1,56 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/browser"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/git"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/output"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/prompt"
 )
 
 const cherryPickPRLabel = "cherry-pick 🍒"
 
+// errAlreadyApplied is returned when every commit is already on the target
+// branch, so there is nothing to cherry-pick for that release.
+var errAlreadyApplied = errors.New("all commits are already applied")
+
 // CherryPickOptions holds options for the cherry-pick command
 type CherryPickOptions struct {
-	Releases  []string
-	Assignees []string
-	DryRun    bool
-	Yes       bool
-	NoVerify  bool
-	Continue  bool
-	Dispatch  bool
+	Releases     []string
+	Assignees    []string
+	Labels       []string
+	Branch       string
+	PRTitle      string
+	KeepGoing    bool
+	DryRun       bool
+	Yes          bool
+	NoVerify     bool
+	Continue     bool
+	Abort        bool
+	Status       bool
+	Dispatch     bool
+	ListReleases bool
+	Format       string
+	Onto         string
+	Cleanup      bool
+	Parallel     bool
+	Force        bool
+	Open         bool
 }
 
 // NewCherryPickCommand creates a new cherry-pick command
@@ -52,29 +75,108 @@ This command will:
   5. Switch back to the original branch
 
 Multiple commits will be cherry-picked in the order specified, similar to git cherry-pick.
-The --release flag can be specified multiple times to cherry-pick to multiple release branches.
+The --release flag can be specified multiple times (or as a comma-separated
+list; --releases is an alias) to cherry-pick to multiple release branches. Each
+release gets its own hotfix branch and PR, progress is recorded so --continue
+resumes after a conflict without redoing finished releases, and a summary of
+the PR URLs is printed at the end.
 
 If a cherry-pick hits a merge conflict, resolve it manually, then run:
   $ ods cherry-pick --continue
 
+To give up instead, run --abort: it aborts the git cherry-pick, switches back
+to your original branch, restores any stashed changes, and discards the ods
+state. Hotfix branches and PRs already created for other releases are kept.
+Use --status to see where an interrupted cherry-pick stands: the commits, which
+releases are done, failed, or pending, and whether git is mid-cherry-pick with
+unresolved conflicts.
+
+With --keep-going, a release that fails (e.g. on a conflict) is aborted and
+recorded, and the remaining releases are still attempted. A pass/fail summary
+is printed at the end; run --continue to retry the failed releases one at a
+time and resolve their conflicts.
+
+Commits that are already on a release branch (by SHA or by subject, e.g. after
+a manual backport) are left out, and a release that already has all of them is
+skipped. --force cherry-picks every commit regardless. If a commit only turns out to be empty
+during the cherry-pick, it is skipped with --keep-going and aborted otherwise.
+
 With --dispatch, the commit(s)/PR(s) are resolved locally and the
 post-merge-beta-cherry-pick GitHub workflow is triggered to perform the
 cherry-pick in CI instead of running locally. The workflow auto-detects the
 latest release unless --release is supplied. Requires the workflow (with its
 workflow_dispatch trigger) to already be on the default branch.
 
+Use --list-releases to see which release branches exist on origin (the latest
+stable release is marked; suffixed branches such as release/v2.5-hotfix are
+listed separately) before choosing --release targets. Add --format json or
+--format csv for scriptable output.
+
+Use --onto to backport to an arbitrary branch (e.g. a customer branch) instead
+of a release branch. The release is not auto-detected and the hotfix branch is
+created from origin/<onto>.
+
+With --dry-run, the release(s) are resolved (including auto-detection) and the
+target branch, hotfix branch, and PR title and body for each are printed;
+nothing is stashed, checked out, cherry-picked, pushed, or created.
+
+With --cleanup, each local hotfix branch is deleted after its PR is created
+(the pushed branch is kept for the PR), so only your original branch and
+stash are left as they were before the backport.
+
+With --parallel (two or more --release targets), each release gets its own
+temporary git worktree and the cherry-picks, pushes, and PR creations run
+concurrently; your checkout is never switched or stashed. A release that
+conflicts is aborted and recorded like --keep-going, so --continue retries it
+in your checkout where the conflict can be resolved.
+
 Example usage:
 
 	$ ods cherry-pick foo123 bar456 --release 2.5 --release 2.6
 	$ ods cp foo123 --release 2.5
+	$ ods cp foo123 --releases v2.10,v2.11,v2.12
 	$ ods cp 1234 --release 2.5   # cherry-pick merge commit of PR #1234
-	$ ods cp 1234 --dispatch      # trigger the cherry-pick workflow for PR #1234`,
+	$ ods cp 1234 --release 2.11 --branch fix-login   # pushes hotfix/fix-login-v2.11
+	$ ods cp 1234 --dispatch      # trigger the cherry-pick workflow for PR #1234
+	$ ods cp --list-releases      # show remote release branches
+	$ ods cp 1234 --onto customer/acme   # pushes hotfix/<sha>-customer-acme
+	$ ods cp 1234 --release 2.5 --release 2.6 --release 2.7 --parallel`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			cont, _ := cmd.Flags().GetBool("continue")
 			dispatch, _ := cmd.Flags().GetBool("dispatch")
+			listReleases, _ := cmd.Flags().GetBool("list-releases")
+			for _, name := range []string{"abort", "status"} {
+				if set, _ := cmd.Flags().GetBool(name); set {
+					if cont || dispatch || listReleases || len(args) > 0 {
+						return fmt.Errorf("--%s cannot be combined with other arguments", name)
+					}
+					return nil
+				}
+			}
 			if cont && dispatch {
 				return fmt.Errorf("--continue and --dispatch cannot be used together")
 			}
+			if onto, _ := cmd.Flags().GetString("onto"); onto != "" {
+				releases, _ := cmd.Flags().GetStringSlice("release")
+				if len(releases) > 0 || dispatch || cont {
+					return fmt.Errorf("--onto cannot be combined with --release, --dispatch, or --continue")
+				}
+			}
+			if parallel, _ := cmd.Flags().GetBool("parallel"); parallel {
+				releases, _ := cmd.Flags().GetStringSlice("release")
+				if len(releases) < 2 || cont || dispatch {
+					return fmt.Errorf("--parallel requires at least two --release targets and cannot be combined with --continue or --dispatch")
+				}
+			}
+			if cmd.Flags().Changed("format") && !listReleases {
+				return fmt.Errorf("--format can only be used with --list-releases")
+			}
+			if listReleases {
+				if cont || dispatch || len(args) > 0 {
+					return fmt.Errorf("--list-releases cannot be combined with other arguments")
+				}
+				return nil
+			}
 			if cont {
 				if len(args) > 0 {
 					return fmt.Errorf("--continue does not accept positional arguments")
@@ -88,8 +190,14 @@ Example usage:
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			switch {
+			case opts.ListReleases:
+				runCherryPickListReleases(opts.Format)
 			case opts.Continue:
 				runCherryPickContinue()
+			case opts.Abort:
+				runCherryPickAbort()
+			case opts.Status:
+				runCherryPickStatus()
 			case opts.Dispatch:
 				runCherryPickDispatch(args, opts)
 			default:
@@ -99,11 +207,30 @@ Example usage:
 	}
 
 	cmd.Flags().BoolVar(&opts.Continue, "continue", false, "Resume a cherry-pick after manual conflict resolution")
-	cmd.Flags().StringSliceVar(&opts.Releases, "release", []string{}, "Release version(s) to cherry-pick to (e.g., 1.0, v1.1). 'v' prefix is optional. Can be specified multiple times.")
-	cmd.Flags().StringSliceVar(&opts.Assignees, "assignee", nil, "GitHub assignee(s) for the created PR. Can be specified multiple times or as comma-separated values.")
-	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Perform all local operations but skip pushing to remote and creating PRs")
+	cmd.Flags().BoolVar(&opts.Abort, "abort", false, "Cancel an in-progress cherry-pick and return to the original branch")
+	cmd.Flags().BoolVar(&opts.Status, "status", false, "Show the state of an in-progress cherry-pick")
+	cmd.Flags().StringSliceVar(&opts.Releases, "release", []string{}, "Release version(s) to cherry-pick to (e.g., 1.0, v1.1). 'v' prefix is optional. Can be specified multiple times or comma-separated (alias: --releases).")
+	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "releases" {
+			name = "release"
+		}
+		return pflag.NormalizedName(name)
+	})
+	cmd.Flags().StringSliceVar(&opts.Assignees, "assignee", nil, "GitHub assignee(s) for the created PR. Can be specified multiple times or as comma-separated values. Defaults to $CHERRY_PICK_ASSIGNEE if set, else yourself (@me)")
+	cmd.Flags().StringSliceVar(&opts.Labels, "label", nil, "Extra label(s) for the created PR, in addition to the cherry-pick label. Can be specified multiple times or as comma-separated values.")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Name for the hotfix branch (becomes hotfix/<branch>-<release>). Defaults to the short SHA(s) of the commit(s)")
+	cmd.Flags().StringVar(&opts.PRTitle, "pr-title", "", "Title for the created PR(s), used verbatim. Defaults to the commit subject, or a generated backport title for multiple commits")
+	cmd.Flags().BoolVar(&opts.KeepGoing, "keep-going", false, "When targeting multiple releases, continue with the remaining releases after one fails")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Preview the target and hotfix branches and the PR(s) that would be created, without touching any branches")
 	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Skip confirmation prompts and automatically proceed")
 	cmd.Flags().BoolVar(&opts.NoVerify, "no-verify", false, "Skip pre-commit and commit-msg hooks for cherry-pick and push")
+	cmd.Flags().BoolVar(&opts.ListReleases, "list-releases", false, "List the release branches on origin and exit")
+	cmd.Flags().StringVar(&opts.Format, "format", string(output.FormatTable), "Output format for --list-releases: table, json, or csv")
+	cmd.Flags().StringVar(&opts.Onto, "onto", "", "Cherry-pick onto this branch instead of a release branch (skips release auto-detection)")
+	cmd.Flags().BoolVar(&opts.Cleanup, "cleanup", false, "Delete each local hotfix branch after its PR is created (the remote branch is kept)")
+	cmd.Flags().BoolVar(&opts.Open, "open", false, "Open each created PR in your browser")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Cherry-pick every commit even if it already appears to be on the target branch")
+	cmd.Flags().BoolVar(&opts.Parallel, "parallel", false, "With multiple --release targets, cherry-pick each release in its own worktree concurrently")
 	cmd.Flags().BoolVar(&opts.Dispatch, "dispatch", false, "Resolve the commit(s) locally, then trigger the post-merge-beta-cherry-pick GitHub workflow instead of cherry-picking locally")
 
 	return cmd
@@ -112,6 +239,18 @@ Example usage:
 func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 	git.CheckGitHubCLI()
 
+	if opts.Branch != "" {
+		if err := validateBranchSuffix(opts.Branch); err != nil {
+			log.Fatalf("Invalid --branch: %v", err)
+		}
+	}
+	if opts.Onto != "" {
+		opts.Onto = strings.TrimPrefix(opts.Onto, "origin/")
+		if !git.IsValidBranchName(opts.Onto) {
+			log.Fatalf("Invalid --onto: %q is not a valid git branch name", opts.Onto)
+		}
+	}
+
 	// Resolve any PR numbers (e.g. "1234") to their merge commit SHAs
 	commitSHAs, labels := resolveArgs(args)
 	if len(commitSHAs) == 1 {
@@ -121,7 +260,7 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 	}
 
 	if opts.DryRun {
-		log.Warning("=== DRY RUN MODE: No remote operations will be performed ===")
+		log.Warning("=== DRY RUN MODE: No branches will be touched and nothing will be pushed ===")
 	}
 
 	// Save the current branch to switch back later
@@ -131,10 +270,15 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 	}
 	log.Debugf("Original branch: %s", originalBranch)
 
-	// Stash any uncommitted changes before switching branches
-	stashResult, err := git.StashChanges()
-	if err != nil {
-		log.Fatalf("Failed to stash changes: %v", err)
+	// Stash any uncommitted changes before switching branches. A dry run
+	// never switches, and --parallel works in separate worktrees, so both
+	// leave the working tree alone.
+	stashResult := &git.StashResult{}
+	if !opts.DryRun && !opts.Parallel {
+		stashResult, err = git.StashChanges()
+		if err != nil {
+			log.Fatalf("Failed to stash changes: %v", err)
+		}
 	}
 
 	// Fetch commits from remote before cherry-picking
@@ -142,9 +286,11 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 		log.Warnf("Failed to fetch commits: %v", err)
 	}
 
-	// Get the short SHA(s) for branch naming
+	// Get the short SHA(s) for branch naming, unless the user named the branch
 	var branchSuffix string
-	if len(commitSHAs) == 1 {
+	if opts.Branch != "" {
+		branchSuffix = strings.TrimPrefix(opts.Branch, "hotfix/")
+	} else if len(commitSHAs) == 1 {
 		shortSHA := commitSHAs[0]
 		if len(shortSHA) > 8 {
 			shortSHA = shortSHA[:8]
@@ -165,7 +311,10 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 
 	// Determine which releases to target
 	var releases []string
-	if len(opts.Releases) > 0 {
+	if opts.Onto != "" {
+		log.Infof("Cherry-picking onto branch: %s", opts.Onto)
+		releases = []string{opts.Onto}
+	} else if len(opts.Releases) > 0 {
 		// Normalize versions to ensure they have 'v' prefix
 		for _, rel := range opts.Releases {
 			releases = append(releases, normalizeVersion(rel))
@@ -206,7 +355,9 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 	}
 
 	var prTitle string
-	if len(commitSHAs) == 1 {
+	if opts.PRTitle != "" {
+		prTitle = opts.PRTitle
+	} else if len(commitSHAs) == 1 {
 		if commitMessages[0] != "" {
 			prTitle = commitMessages[0]
 		} else {
@@ -218,7 +369,7 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 		}
 	} else {
 		// For multiple commits, use a generic title
-		prTitle = fmt.Sprintf("chore(hotfix): cherry-pick %d commits", len(commitSHAs))
+		prTitle = fmt.Sprintf("chore(hotfix): backport %d commits", len(commitSHAs))
 	}
 
 	// Save state so --continue can resume if a conflict occurs
@@ -229,24 +380,47 @@ func runCherryPick(cmd *cobra.Command, args []string, opts *CherryPickOptions) {
 	}
 
 	state := &git.CherryPickState{
-		OriginalBranch: originalBranch,
-		CommitSHAs:     commitSHAs,
-		CommitMessages: commitMessages,
-		Releases:       releases,
-		Assignees:      assignees,
-		Stashed:        stashResult.Stashed,
-		NoVerify:       opts.NoVerify,
-		DryRun:         opts.DryRun,
-		BranchSuffix:   branchSuffix,
-		PRTitle:        prTitle,
+		OriginalBranch:  originalBranch,
+		CommitSHAs:      commitSHAs,
+		CommitMessages:  commitMessages,
+		Releases:        releases,
+		Assignees:       assignees,
+		Labels:          dedupeNonEmpty(opts.Labels),
+		Stashed:         stashResult.Stashed,
+		NoVerify:        opts.NoVerify,
+		BranchSuffix:    branchSuffix,
+		PRTitle:         prTitle,
+		PRTitleOverride: opts.PRTitle != "",
+		KeepGoing:       opts.KeepGoing || opts.Parallel,
+		Onto:            opts.Onto,
+		Cleanup:         opts.Cleanup,
+		Force:           opts.Force,
+		Open:            opts.Open,
+	}
+	if opts.DryRun {
+		printCherryPickPreview(os.Stdout, state)
+		return
 	}
 	if err := git.SaveCherryPickState(state); err != nil {
 		log.Warnf("Failed to save cherry-pick state (--continue won't work): %v", err)
 	}
 
+	if opts.Parallel {
+		finishCherryPickParallel(state, stashResult)
+		return
+	}
 	finishCherryPick(state, stashResult)
 }
 
+// releaseOutcome records the result of cherry-picking to a single release.
+type releaseOutcome struct {
+	Release string
+	Branch  string // local hotfix branch, set on success
+	PRURL   string
+	Skipped bool // every commit was already on the target branch
+	Err     error
+}
+
 // finishCherryPick processes each release (cherry-pick remaining commits, push, create PR),
 // then switches back to the original branch and cleans up.
 func finishCherryPick(state *git.CherryPickState, stashResult *git.StashResult) {
@@ -255,7 +429,10 @@ func finishCherryPick(state *git.CherryPickState, stashResult *git.StashResult)
 		completed[r] = true
 	}
 
-	prURLs := []string{}
+	// Failed releases are retried on this pass; they're re-recorded if they fail again
+	state.FailedReleases = nil
+
+	var outcomes []releaseOutcome
 	for _, release := range state.Releases {
 		if completed[release] {
 			log.Infof("Release %s already completed, skipping", release)
@@ -263,8 +440,29 @@ func finishCherryPick(state *git.CherryPickState, stashResult *git.StashResult)
 		}
 
 		log.Infof("Processing release %s", release)
-		prTitleWithRelease := fmt.Sprintf("%s to release %s", state.PRTitle, release)
-		prURL, err := cherryPickToRelease(state.CommitSHAs, state.CommitMessages, state.BranchSuffix, release, prTitleWithRelease, state.Assignees, state.DryRun, state.NoVerify)
+		targetBranch, targetLabel := cherryPickTarget(state, release)
+		prURL, err := cherryPickToRelease(state.CommitSHAs, state.CommitMessages, state.BranchSuffix, targetBranch, targetLabel, prTitleForRelease(state, release), state.Assignees, state.Labels, state.NoVerify, state.KeepGoing, state.Force)
+		if errors.Is(err, errAlreadyApplied) {
+			log.Warnf("Skipping release %s: %v", release, err)
+			state.CompletedReleases = append(state.CompletedReleases, release)
+			if saveErr := git.SaveCherryPickState(state); saveErr != nil {
+				log.Warnf("Failed to update state file: %v", saveErr)
+			}
+			outcomes = append(outcomes, releaseOutcome{Release: release, Skipped: true})
+			continue
+		}
+		if err != nil && state.KeepGoing {
+			log.Errorf("Failed to cherry-pick to release %s: %v", release, err)
+			if abortErr := git.AbortCherryPick(); abortErr != nil {
+				log.Warnf("Failed to abort cherry-pick: %v", abortErr)
+			}
+			state.FailedReleases = append(state.FailedReleases, release)
+			if saveErr := git.SaveCherryPickState(state); saveErr != nil {
+				log.Warnf("Failed to update state file: %v", saveErr)
+			}
+			outcomes = append(outcomes, releaseOutcome{Release: release, Err: err})
+			continue
+		}
 		if err != nil {
 			if strings.Contains(err.Error(), "merge conflict") {
 				if stashResult.Stashed {
@@ -286,35 +484,421 @@ func finishCherryPick(state *git.CherryPickState, stashResult *git.StashResult)
 			log.Warnf("Failed to update state file: %v", saveErr)
 		}
 
-		if prURL != "" {
-			prURLs = append(prURLs, prURL)
-		}
+		outcomes = append(outcomes, releaseOutcome{Release: release, Branch: hotfixBranchName(state.BranchSuffix, targetLabel), PRURL: prURL})
 	}
 
 	log.Infof("Switching back to original branch: %s", state.OriginalBranch)
 	if err := git.RunCommand("switch", "--quiet", state.OriginalBranch); err != nil {
 		log.Warnf("Failed to switch back to original branch: %v", err)
+	} else if state.Cleanup {
+		deleteHotfixBranches(outcomes)
 	}
 
 	git.RestoreStash(stashResult)
-	git.CleanCherryPickState()
+	// The state is also where the stash flag lives; it's been restored now
+	state.Stashed = false
+
+	if state.Open {
+		openPRs(outcomes)
+	}
+
+	if len(state.FailedReleases) == 0 {
+		git.CleanCherryPickState()
+	} else if err := git.SaveCherryPickState(state); err != nil {
+		log.Warnf("Failed to update state file: %v", err)
+	}
+
+	if state.KeepGoing {
+		printReleaseOutcomes(outcomes)
+		if len(state.FailedReleases) > 0 {
+			log.Fatalf("%d of %d release(s) failed: %s. Run 'ods cherry-pick --continue' to retry them and resolve conflicts.",
+				len(state.FailedReleases), len(outcomes), strings.Join(state.FailedReleases, ", "))
+		}
+		return
+	}
+
+	if len(outcomes) > 1 {
+		printReleaseOutcomes(outcomes)
+	}
+}
+
+// finishCherryPickParallel is the --parallel counterpart of finishCherryPick.
+// Each pending release is checked out into its own temporary worktree so the
+// cherry-picks, pushes, and PR creations can run concurrently without touching
+// the user's checkout. Failed releases are aborted and recorded in the state
+// so --continue retries them serially.
+func finishCherryPickParallel(state *git.CherryPickState, stashResult *git.StashResult) {
+	completed := make(map[string]bool, len(state.CompletedReleases))
+	for _, r := range state.CompletedReleases {
+		completed[r] = true
+	}
+	state.FailedReleases = nil
+
+	var pending []string
+	fetchArgs := []string{"fetch", "--prune", "--quiet", "origin"}
+	for _, release := range state.Releases {
+		if completed[release] {
+			log.Infof("Release %s already completed, skipping", release)
+			continue
+		}
+		pending = append(pending, release)
+		targetBranch, _ := cherryPickTarget(state, release)
+		fetchArgs = append(fetchArgs, targetBranch)
+	}
+
+	// A single fetch up front avoids concurrent fetches contending for ref locks
+	log.Infof("Fetching %d target branch(es)", len(pending))
+	if err := git.RunCommand(fetchArgs...); err != nil {
+		git.RestoreStash(stashResult)
+		log.Fatalf("Failed to fetch target branches: %v", err)
+	}
+
+	worktreeRoot, err := os.MkdirTemp("", "ods-cherry-pick-")
+	if err != nil {
+		git.RestoreStash(stashResult)
+		log.Fatalf("Failed to create worktree directory: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(worktreeRoot) }()
+
+	// Worktrees are added one at a time since each writes to the shared .git dir
+	outcomes := make([]releaseOutcome, len(pending))
+	worktrees := make([]string, len(pending))
+	for i, release := range pending {
+		outcomes[i].Release = release
+		_, targetLabel := cherryPickTarget(state, release)
+		worktrees[i] = filepath.Join(worktreeRoot, targetLabel)
+		if err := addHotfixWorktree(worktrees[i], state, release); err != nil {
+			outcomes[i].Err = err
+			worktrees[i] = ""
+		}
+	}
+
+	log.Infof("Cherry-picking to %d release(s) in parallel", len(pending))
+	var wg sync.WaitGroup
+	for i, release := range pending {
+		if worktrees[i] == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, release string) {
+			defer wg.Done()
+			outcomes[i].PRURL, outcomes[i].Err = cherryPickInWorktree(worktrees[i], state, release)
+		}(i, release)
+	}
+	wg.Wait()
+
+	for i, o := range outcomes {
+		if worktrees[i] != "" {
+			if err := git.RunCommand("worktree", "remove", "--force", worktrees[i]); err != nil {
+				log.Warnf("Failed to remove worktree %s: %v", worktrees[i], err)
+			}
+		}
+		if errors.Is(o.Err, errAlreadyApplied) {
+			log.Warnf("Skipping release %s: %v", o.Release, o.Err)
+			outcomes[i].Err = nil
+			outcomes[i].Skipped = true
+			state.CompletedReleases = append(state.CompletedReleases, o.Release)
+			continue
+		}
+		if o.Err != nil {
+			log.Errorf("Failed to cherry-pick to release %s: %v", o.Release, o.Err)
+			state.FailedReleases = append(state.FailedReleases, o.Release)
+			continue
+		}
+		_, targetLabel := cherryPickTarget(state, o.Release)
+		outcomes[i].Branch = hotfixBranchName(state.BranchSuffix, targetLabel)
+		state.CompletedReleases = append(state.CompletedReleases, o.Release)
+	}
+	if err := git.RunCommand("worktree", "prune"); err != nil {
+		log.Warnf("Failed to prune worktrees: %v", err)
+	}
+
+	if state.Cleanup {
+		deleteHotfixBranches(outcomes)
+	}
+
+	git.RestoreStash(stashResult)
+	state.Stashed = false
+
+	if state.Open {
+		openPRs(outcomes)
+	}
+
+	if len(state.FailedReleases) == 0 {
+		git.CleanCherryPickState()
+	} else if err := git.SaveCherryPickState(state); err != nil {
+		log.Warnf("Failed to update state file: %v", err)
+	}
+
+	printReleaseOutcomes(outcomes)
+	if len(state.FailedReleases) > 0 {
+		log.Fatalf("%d of %d release(s) failed: %s. Run 'ods cherry-pick --continue' to retry them and resolve conflicts.",
+			len(state.FailedReleases), len(outcomes), strings.Join(state.FailedReleases, ", "))
+	}
+}
+
+// addHotfixWorktree checks out the hotfix branch for release into dir,
+// creating the branch from the target branch if it doesn't exist yet.
+func addHotfixWorktree(dir string, state *git.CherryPickState, release string) error {
+	targetBranch, targetLabel := cherryPickTarget(state, release)
+	hotfixBranch := hotfixBranchName(state.BranchSuffix, targetLabel)
+
+	args := []string{"worktree", "add", "--quiet"}
+	if git.BranchExists(hotfixBranch) {
+		log.Infof("Hotfix branch %s already exists, reusing it", hotfixBranch)
+		args = append(args, dir, hotfixBranch)
+	} else {
+		log.Infof("Creating hotfix branch: %s", hotfixBranch)
+		args = append(args, "--no-track", "-b", hotfixBranch, dir, fmt.Sprintf("origin/%s", targetBranch))
+	}
+	if err := git.RunCommandVerboseOnError(args...); err != nil {
+		return fmt.Errorf("failed to create worktree for %s: %w", hotfixBranch, err)
+	}
+	return nil
+}
+
+// cherryPickInWorktree cherry-picks the pending commits in the worktree at
+// dir, then pushes the hotfix branch and creates its PR. Git output is only
+// shown on failure so concurrent releases don't interleave. A failed
+// cherry-pick is aborted, leaving the branch as it was for --continue.
+func cherryPickInWorktree(dir string, state *git.CherryPickState, release string) (string, error) {
+	targetBranch, targetLabel := cherryPickTarget(state, release)
+	hotfixBranch := hotfixBranchName(state.BranchSuffix, targetLabel)
+
+	if !state.Force && len(commitsNotApplied(state.CommitSHAs, "origin/"+targetBranch)) == 0 {
+		return "", fmt.Errorf("%w to %s", errAlreadyApplied, targetBranch)
+	}
+
+	var commitsToCherry []string
+	for _, sha := range state.CommitSHAs {
+		if git.IsCommitAppliedOnBranch(sha, hotfixBranch) {
+			log.Infof("[%s] Commit %s already applied, skipping", release, sha)
+			continue
+		}
+		commitsToCherry = append(commitsToCherry, sha)
+	}
+
+	if len(commitsToCherry) > 0 {
+		log.Infof("[%s] Cherry-picking %d commit(s)", release, len(commitsToCherry))
+		if err := performCherryPick(dir, commitsToCherry, state.KeepGoing); err != nil {
+			if git.IsCherryPickInProgressIn(dir) {
+				if abortErr := git.RunCommandVerboseOnError("-C", dir, "cherry-pick", "--abort"); abortErr != nil {
+					log.Warnf("[%s] Failed to abort cherry-pick: %v", release, abortErr)
+				}
+			}
+			return "", err
+		}
+	}
+
+	// No -u: setting upstreams concurrently would contend for the config lock
+	log.Infof("[%s] Pushing hotfix branch: %s", release, hotfixBranch)
+	pushArgs := []string{"-C", dir, "push", "origin", hotfixBranch}
+	if state.NoVerify {
+		pushArgs = []string{"-C", dir, "push", "--no-verify", "origin", hotfixBranch}
+	}
+	if err := git.RunCommandVerboseOnError(pushArgs...); err != nil {
+		return "", fmt.Errorf("failed to push hotfix branch: %w", err)
+	}
+
+	prURL, err := createCherryPickPR(hotfixBranch, targetBranch, prTitleForRelease(state, release), state.CommitSHAs, state.CommitMessages, state.Assignees, state.Labels)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	log.Infof("[%s] PR created successfully: %s", release, prURL)
+	verifyCherryPickPR(prURL, targetBranch)
+	return prURL, nil
+}
+
+// deleteHotfixBranches deletes the local hotfix branch of each successful
+// release. The remote branches are left for their PRs.
+func deleteHotfixBranches(outcomes []releaseOutcome) {
+	for _, o := range outcomes {
+		if o.Err != nil || o.Branch == "" {
+			continue
+		}
+		if err := git.RunCommand("branch", "--quiet", "-D", o.Branch); err != nil {
+			log.Warnf("Failed to delete local branch %s: %v", o.Branch, err)
+			continue
+		}
+		log.Infof("Deleted local branch %s", o.Branch)
+	}
+}
+
+// Release statuses shown in the cherry-pick plan and summary tables.
+const (
+	releaseStatusPending  = "pending"
+	releaseStatusDone     = "done"
+	releaseStatusConflict = "conflict"
+	releaseStatusFailed   = "failed"
+	releaseStatusSkipped  = "skipped"
+)
+
+// releaseRow is one line of the cherry-pick plan or summary table.
+type releaseRow struct {
+	Release string
+	Branch  string
+	PRURL   string
+	Status  string
+}
+
+// writeReleaseTable writes an aligned release table. The status is the last
+// column so coloring it doesn't throw off the tabwriter alignment.
+func writeReleaseTable(w io.Writer, rows []releaseRow, color bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RELEASE\tBRANCH\tPR\tSTATUS")
+	for _, r := range rows {
+		pr := r.PRURL
+		if pr == "" {
+			pr = "-"
+		}
+		status := r.Status
+		if color {
+			status = colorizeReleaseStatus(status)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Release, r.Branch, pr, status)
+	}
+	_ = tw.Flush()
+}
+
+// colorizeReleaseStatus wraps a release status in its ANSI color.
+func colorizeReleaseStatus(status string) string {
+	var code string
+	switch status {
+	case releaseStatusDone:
+		code = "32"
+	case releaseStatusConflict:
+		code = "33"
+	case releaseStatusFailed:
+		code = "31"
+	default:
+		return status
+	}
+	return "\x1b[" + code + "m" + status + "\x1b[0m"
+}
+
+// printCherryPickPreview writes what a --dry-run cherry-pick would do for each
+// release: the target and hotfix branches, and the PR it would open.
+func printCherryPickPreview(w io.Writer, state *git.CherryPickState) {
+	for i, release := range state.Releases {
+		targetBranch, targetLabel := cherryPickTarget(state, release)
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "Target branch: %s\n", targetBranch)
+		_, _ = fmt.Fprintf(w, "Hotfix branch: %s\n", hotfixBranchName(state.BranchSuffix, targetLabel))
+		_, _ = fmt.Fprintf(w, "PR title:      %s\n", prTitleForRelease(state, release))
+		if len(state.Assignees) > 0 {
+			_, _ = fmt.Fprintf(w, "Assignees:     %s\n", strings.Join(state.Assignees, ", "))
+		}
+		_, _ = fmt.Fprintf(w, "Labels:        %s\n", strings.Join(cherryPickPRLabels(state.Labels), ", "))
+		_, _ = fmt.Fprintln(w, "PR body:")
+		for _, line := range strings.Split(strings.TrimRight(cherryPickPRBody(targetBranch, state.CommitSHAs, state.CommitMessages), "\n"), "\n") {
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// releaseStateRows returns one row per release in state, marked done or
+// failed as recorded by earlier runs, and pending otherwise.
+func releaseStateRows(state *git.CherryPickState) []releaseRow {
+	statuses := make(map[string]string, len(state.CompletedReleases)+len(state.FailedReleases))
+	for _, r := range state.FailedReleases {
+		statuses[r] = releaseStatusFailed
+	}
+	for _, r := range state.CompletedReleases {
+		statuses[r] = releaseStatusDone
+	}
 
-	for i, prURL := range prURLs {
-		log.Infof("PR %d: %s", i+1, prURL)
+	rows := make([]releaseRow, 0, len(state.Releases))
+	for _, release := range state.Releases {
+		_, targetLabel := cherryPickTarget(state, release)
+		status, ok := statuses[release]
+		if !ok {
+			status = releaseStatusPending
+		}
+		rows = append(rows, releaseRow{Release: release, Branch: hotfixBranchName(state.BranchSuffix, targetLabel), Status: status})
 	}
+	return rows
+}
+
+// openPRs opens each created PR in the browser. When that isn't possible the
+// URL is logged instead so it can still be clicked.
+func openPRs(outcomes []releaseOutcome) {
+	for _, o := range outcomes {
+		if o.PRURL == "" {
+			continue
+		}
+		if err := browser.Open(o.PRURL); err != nil {
+			log.Warnf("Could not open a browser (%v). PR: %s", err, o.PRURL)
+		}
+	}
+}
+
+// printReleaseOutcomes prints a consolidated pass/fail table, one row per release.
+func printReleaseOutcomes(outcomes []releaseOutcome) {
+	rows := make([]releaseRow, 0, len(outcomes))
+	for _, o := range outcomes {
+		row := releaseRow{Release: o.Release, Branch: o.Branch, PRURL: o.PRURL, Status: releaseStatusDone}
+		if o.Skipped {
+			row.Status = releaseStatusSkipped
+		}
+		if o.Err != nil {
+			row.Status = releaseStatusFailed
+			if strings.Contains(o.Err.Error(), "merge conflict") {
+				row.Status = releaseStatusConflict
+			}
+		}
+		if row.Branch == "" {
+			row.Branch = "-"
+		}
+		rows = append(rows, row)
+	}
+
+	log.Info("Cherry-pick summary:")
+	writeReleaseTable(os.Stdout, rows, colorEnabled(false))
+}
+
+// prTitleForRelease returns the PR title for a release. A --pr-title override is
+// used verbatim; generated titles get the target release appended.
+func prTitleForRelease(state *git.CherryPickState, release string) string {
+	if state.PRTitleOverride {
+		return state.PRTitle
+	}
+	if state.Onto != "" {
+		return fmt.Sprintf("%s to %s", state.PRTitle, state.Onto)
+	}
+	return fmt.Sprintf("%s to release %s", state.PRTitle, release)
+}
+
+// cherryPickTarget returns the branch a release entry is cherry-picked onto and
+// the label used to name its hotfix branch. With --onto, the entry is the
+// target branch itself; otherwise it's a version whose branch is release/<version>.
+func cherryPickTarget(state *git.CherryPickState, release string) (branch, label string) {
+	if state.Onto != "" {
+		return state.Onto, strings.ReplaceAll(state.Onto, "/", "-")
+	}
+	return fmt.Sprintf("release/%s", release), release
 }
 
 // runCherryPickContinue resumes a cherry-pick after manual conflict resolution.
 // It finishes any in-progress git cherry-pick, then falls into the normal
 // cherryPickToRelease path which handles skip-applied-commits, push, and PR creation.
 func runCherryPickContinue() {
-	git.CheckGitHubCLI()
-
 	state, err := git.LoadCherryPickState()
+	if errors.Is(err, git.ErrNoCherryPickState) {
+		if git.IsCherryPickInProgress() {
+			log.Fatal("A git cherry-pick is in progress, but it wasn't started by ods.\nFinish it with: git cherry-pick --continue (or --abort)")
+		}
+		log.Info("No cherry-pick in progress; nothing to continue.")
+		return
+	}
 	if err != nil {
 		log.Fatalf("Cannot continue: %v", err)
 	}
 
+	git.CheckGitHubCLI()
+
 	log.Infof("Resuming cherry-pick (original branch: %s, releases: %v)", state.OriginalBranch, state.Releases)
 
 	// If a rebase is in progress (REBASE_HEAD exists), it must be resolved first
@@ -330,12 +914,123 @@ func runCherryPickContinue() {
 		}
 	}
 
+	// Failed releases from a --keep-going run are retried one at a time so the
+	// user can resolve each conflict, rather than being skipped again.
+	state.KeepGoing = false
+
 	// Re-use the normal per-release flow: cherryPickToRelease already handles
 	// "branch exists → skip applied commits → push → create PR"
 	stashResult := &git.StashResult{Stashed: state.Stashed}
+	if !state.Stashed {
+		// A --parallel run never stashed, but the serial retry switches branches
+		stashResult, err = git.StashChanges()
+		if err != nil {
+			log.Fatalf("Failed to stash changes: %v", err)
+		}
+		state.Stashed = stashResult.Stashed
+	}
 	finishCherryPick(state, stashResult)
 }
 
+// runCherryPickAbort cancels a cherry-pick started by ods: it aborts any
+// in-progress git cherry-pick (or rebase), switches back to the original
+// branch, restores the stash, and removes the state file.
+func runCherryPickAbort() {
+	state, err := git.LoadCherryPickState()
+	if errors.Is(err, git.ErrNoCherryPickState) {
+		if git.IsCherryPickInProgress() {
+			log.Fatal("A git cherry-pick is in progress, but it wasn't started by ods.\nAbort it with: git cherry-pick --abort")
+		}
+		log.Info("No cherry-pick in progress; nothing to abort.")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Cannot abort: %v", err)
+	}
+
+	if git.IsRebaseInProgress() {
+		log.Info("Aborting in-progress rebase...")
+		if err := git.RunCommand("rebase", "--abort"); err != nil {
+			log.Fatalf("git rebase --abort failed: %v", err)
+		}
+	}
+	if git.IsCherryPickInProgress() {
+		log.Info("Aborting in-progress cherry-pick...")
+		if err := git.AbortCherryPick(); err != nil {
+			log.Fatalf("git cherry-pick --abort failed: %v", err)
+		}
+	}
+
+	log.Infof("Switching back to original branch: %s", state.OriginalBranch)
+	if err := git.RunCommand("switch", "--quiet", state.OriginalBranch); err != nil {
+		// Keep the state so the stash flag isn't lost; the user can retry
+		log.Fatalf("Failed to switch back to %s: %v\nFix the checkout, then re-run: ods cherry-pick --abort", state.OriginalBranch, err)
+	}
+	git.RestoreStash(&git.StashResult{Stashed: state.Stashed})
+	git.CleanCherryPickState()
+
+	if len(state.CompletedReleases) > 0 {
+		log.Infof("Cherry-pick aborted. Releases already completed (%s) keep their hotfix branches and PRs.", strings.Join(state.CompletedReleases, ", "))
+	} else {
+		log.Info("Cherry-pick aborted.")
+	}
+}
+
+// runCherryPickStatus prints the saved state of an interrupted cherry-pick
+// alongside what git is currently doing.
+func runCherryPickStatus() {
+	state, err := git.LoadCherryPickState()
+	if errors.Is(err, git.ErrNoCherryPickState) {
+		if git.IsCherryPickInProgress() {
+			log.Info("A git cherry-pick is in progress, but it wasn't started by ods.")
+			return
+		}
+		log.Info("No cherry-pick in progress.")
+		return
+	}
+	if err != nil {
+		log.Fatalf("Cannot read cherry-pick state: %v", err)
+	}
+
+	writeCherryPickStatus(os.Stdout, state, git.IsCherryPickInProgress(), git.HasMergeConflict(), colorEnabled(false))
+}
+
+// writeCherryPickStatus writes the --status report for state.
+func writeCherryPickStatus(w io.Writer, state *git.CherryPickState, inProgress, conflict bool, color bool) {
+	_, _ = fmt.Fprintf(w, "Original branch: %s\n", state.OriginalBranch)
+	if state.Stashed {
+		_, _ = fmt.Fprintln(w, "Stashed changes: yes (restored when the cherry-pick finishes or is aborted)")
+	}
+
+	_, _ = fmt.Fprintf(w, "\nCommits (%d):\n", len(state.CommitSHAs))
+	for i, sha := range state.CommitSHAs {
+		msg := ""
+		if i < len(state.CommitMessages) {
+			msg = state.CommitMessages[i]
+		}
+		if len(sha) > 8 {
+			sha = sha[:8]
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s\n", sha, msg)
+	}
+
+	_, _ = fmt.Fprintln(w, "\nReleases:")
+	writeReleaseTable(w, releaseStateRows(state), color)
+
+	_, _ = fmt.Fprintln(w)
+	switch {
+	case conflict:
+		_, _ = fmt.Fprintln(w, "git: cherry-pick in progress with unresolved conflicts")
+		_, _ = fmt.Fprintln(w, "Resolve them and run: ods cherry-pick --continue (or --abort)")
+	case inProgress:
+		_, _ = fmt.Fprintln(w, "git: cherry-pick in progress, conflicts resolved")
+		_, _ = fmt.Fprintln(w, "Run: ods cherry-pick --continue")
+	default:
+		_, _ = fmt.Fprintln(w, "git: no cherry-pick in progress")
+		_, _ = fmt.Fprintln(w, "Run: ods cherry-pick --continue to process the remaining releases (or --abort)")
+	}
+}
+
 // runCherryPickDispatch resolves the given commit(s)/PR(s) locally, then triggers
 // the post-merge-beta-cherry-pick GitHub workflow for each — instead of performing
 // the cherry-pick on the local machine. The workflow auto-detects the latest
@@ -381,15 +1076,30 @@ func runCherryPickDispatch(args []string, opts *CherryPickOptions) {
 	}
 }
 
-// cherryPickToRelease cherry-picks one or more commits to a specific release branch
-func cherryPickToRelease(commitSHAs, commitMessages []string, branchSuffix, version, prTitle string, assignees []string, dryRun, noVerify bool) (string, error) {
-	releaseBranch := fmt.Sprintf("release/%s", version)
-	hotfixBranch := fmt.Sprintf("hotfix/%s-%s", branchSuffix, version)
+// hotfixBranchName returns the local and pushed branch for a backport.
+func hotfixBranchName(branchSuffix, label string) string {
+	return fmt.Sprintf("hotfix/%s-%s", branchSuffix, label)
+}
+
+// cherryPickToRelease cherry-picks one or more commits to a specific release
+// branch (or --onto branch). The hotfix branch is named hotfix/<suffix>-<label>.
+func cherryPickToRelease(commitSHAs, commitMessages []string, branchSuffix, releaseBranch, label, prTitle string, assignees, labels []string, noVerify, skipEmpty, force bool) (string, error) {
+	hotfixBranch := hotfixBranchName(branchSuffix, label)
 
 	// Fetch the release branch
-	log.Infof("Fetching release branch: %s", releaseBranch)
+	log.Infof("Fetching target branch: %s", releaseBranch)
 	if err := git.RunCommand("fetch", "--prune", "--quiet", "origin", releaseBranch); err != nil {
-		return "", fmt.Errorf("failed to fetch release branch %s: %w", releaseBranch, err)
+		return "", fmt.Errorf("failed to fetch target branch %s: %w", releaseBranch, err)
+	}
+
+	// Commits whose changes are already on the target would make git
+	// cherry-pick stop with "nothing to commit", so leave them out up front
+	pending := commitSHAs
+	if !force {
+		pending = commitsNotApplied(commitSHAs, fmt.Sprintf("origin/%s", releaseBranch))
+	}
+	if len(pending) == 0 {
+		return "", fmt.Errorf("%w to %s", errAlreadyApplied, releaseBranch)
 	}
 
 	// Check if hotfix branch already exists
@@ -431,7 +1141,7 @@ func cherryPickToRelease(commitSHAs, commitMessages []string, branchSuffix, vers
 			log.Infof("All commits already exist on branch %s", hotfixBranch)
 		} else {
 			// Cherry-pick only the missing commits
-			if err := performCherryPick(commitsToCherry); err != nil {
+			if err := performCherryPick("", commitsToCherry, skipEmpty); err != nil {
 				return "", err
 			}
 		}
@@ -442,18 +1152,12 @@ func cherryPickToRelease(commitSHAs, commitMessages []string, branchSuffix, vers
 			return "", fmt.Errorf("failed to create hotfix branch: %w", err)
 		}
 
-		// Cherry-pick all commits
-		if err := performCherryPick(commitSHAs); err != nil {
+		// Cherry-pick the commits the release doesn't have yet
+		if err := performCherryPick("", pending, skipEmpty); err != nil {
 			return "", err
 		}
 	}
 
-	if dryRun {
-		log.Warnf("[DRY RUN] Would push hotfix branch: %s", hotfixBranch)
-		log.Warnf("[DRY RUN] Would create PR from %s to %s", hotfixBranch, releaseBranch)
-		return "", nil
-	}
-
 	log.Infof("Pushing hotfix branch: %s", hotfixBranch)
 	pushArgs := []string{"push", "-u", "origin", hotfixBranch}
 	if noVerify {
@@ -465,17 +1169,56 @@ func cherryPickToRelease(commitSHAs, commitMessages []string, branchSuffix, vers
 
 	// Create PR using GitHub CLI
 	log.Info("Creating PR...")
-	prURL, err := createCherryPickPR(hotfixBranch, releaseBranch, prTitle, commitSHAs, commitMessages, assignees)
+	prURL, err := createCherryPickPR(hotfixBranch, releaseBranch, prTitle, commitSHAs, commitMessages, assignees, labels)
 	if err != nil {
 		return "", fmt.Errorf("failed to create PR: %w", err)
 	}
 
 	log.Infof("PR created successfully: %s", prURL)
+	verifyCherryPickPR(prURL, releaseBranch)
 	return prURL, nil
 }
 
-// performCherryPick cherry-picks the given commits
-func performCherryPick(commitSHAs []string) error {
+// verifyCherryPickPR confirms a just-created PR is open and targets the
+// expected release branch. Problems are logged as warnings since the PR
+// itself already exists.
+func verifyCherryPickPR(prURL, releaseBranch string) {
+	info, err := git.GetPRInfo(prURL)
+	if err != nil {
+		log.Warnf("Could not verify PR %s: %v", prURL, err)
+		return
+	}
+	if info.State != "OPEN" {
+		log.Warnf("PR %s is %s, expected OPEN", prURL, info.State)
+	}
+	if info.BaseRefName != releaseBranch {
+		log.Warnf("PR %s targets %s, expected %s", prURL, info.BaseRefName, releaseBranch)
+		return
+	}
+	if info.State == "OPEN" {
+		log.Infof("Verified PR is open against %s", info.BaseRefName)
+	}
+}
+
+// commitsNotApplied returns the commits that aren't yet on branch, logging
+// the ones that are.
+func commitsNotApplied(commitSHAs []string, branch string) []string {
+	var pending []string
+	for _, sha := range commitSHAs {
+		if git.IsCommitAppliedOnBranch(sha, branch) {
+			log.Infof("Commit %s already applied on %s, skipping", sha, branch)
+			continue
+		}
+		pending = append(pending, sha)
+	}
+	return pending
+}
+
+// performCherryPick cherry-picks the given commits in the worktree at dir (""
+// for the current checkout). A commit whose changes turn out to be already
+// applied is skipped with skipEmpty (--keep-going); otherwise the cherry-pick
+// is aborted.
+func performCherryPick(dir string, commitSHAs []string, skipEmpty bool) error {
 	if len(commitSHAs) == 0 {
 		return nil
 	}
@@ -488,35 +1231,43 @@ func performCherryPick(commitSHAs []string) error {
 
 	// Build git cherry-pick command with all commits
 	// Note: git cherry-pick does not support --no-verify; hooks run during cherry-pick
-	cherryPickArgs := []string{"cherry-pick"}
+	cherryPickArgs := git.InDir(dir, "cherry-pick")
 	cherryPickArgs = append(cherryPickArgs, commitSHAs...)
 
-	if err := git.RunCommandVerboseOnError(cherryPickArgs...); err != nil {
+	err := git.RunCommandVerboseOnError(cherryPickArgs...)
+	for err != nil {
 		// Check if this is a merge conflict
-		if git.HasMergeConflict() {
+		if git.HasMergeConflictIn(dir) {
 			log.Error("Cherry-pick failed due to merge conflict!")
-			log.Info("To resolve:")
-			log.Info("  1. Fix the conflicts in the affected files")
-			log.Info("  2. Stage the resolved files: git add <files>")
-			log.Info("  3. Continue: ods cherry-pick --continue")
+			if dir == "" {
+				log.Info("To resolve:")
+				log.Info("  1. Fix the conflicts in the affected files")
+				log.Info("  2. Stage the resolved files: git add <files>")
+				log.Info("  3. Continue: ods cherry-pick --continue")
+			}
 			return fmt.Errorf("merge conflict during cherry-pick")
 		}
 		// Check if cherry-pick is empty (commit already applied with different SHA)
 		// Only skip if there are no staged changes - if user resolved conflicts and staged,
 		// they should run `git cherry-pick --continue` instead
-		if git.IsCherryPickInProgress() {
-			if git.HasStagedChanges() {
-				log.Error("Cherry-pick in progress with staged changes.")
-				log.Info("It looks like you resolved conflicts. Run: git cherry-pick --continue")
-				return fmt.Errorf("cherry-pick in progress with staged changes")
-			}
-			log.Info("Cherry-pick is empty (changes already applied), skipping...")
-			if skipErr := git.RunCommand("cherry-pick", "--skip"); skipErr != nil {
-				return fmt.Errorf("failed to skip empty cherry-pick: %w", skipErr)
+		if !git.IsCherryPickInProgressIn(dir) {
+			return fmt.Errorf("failed to cherry-pick commits: %w", err)
+		}
+		if git.HasStagedChangesIn(dir) {
+			log.Error("Cherry-pick in progress with staged changes.")
+			log.Info("It looks like you resolved conflicts. Run: git cherry-pick --continue")
+			return fmt.Errorf("cherry-pick in progress with staged changes")
+		}
+		if !skipEmpty {
+			if abortErr := git.RunCommand(git.InDir(dir, "cherry-pick", "--abort")...); abortErr != nil {
+				log.Warnf("Failed to abort cherry-pick: %v", abortErr)
 			}
-			return nil
+			return fmt.Errorf("cherry-pick is empty: a commit's changes are already applied on this branch (cherry-pick aborted; use --keep-going to skip such commits)")
 		}
-		return fmt.Errorf("failed to cherry-pick commits: %w", err)
+		// --skip resumes the remaining commits, so it fails again on the next
+		// empty commit or conflict; loop until the sequence finishes
+		log.Info("Cherry-pick is empty (changes already applied), skipping...")
+		err = git.RunCommand(git.InDir(dir, "cherry-pick", "--skip")...)
 	}
 	return nil
 }
@@ -555,6 +1306,52 @@ func resolveArgs(args []string) (commitSHAs []string, labels []string) {
 	return commitSHAs, labels
 }
 
+// validateBranchSuffix checks that the user-supplied --branch value produces a
+// legal git ref once expanded to hotfix/<branch>-<release>.
+func validateBranchSuffix(branch string) error {
+	suffix := strings.TrimPrefix(branch, "hotfix/")
+	if suffix == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+	if !git.IsValidBranchName(fmt.Sprintf("hotfix/%s-v0.0", suffix)) {
+		return fmt.Errorf("%q is not a valid git branch name", branch)
+	}
+	return nil
+}
+
+// runCherryPickListReleases prints the remote release branches, stable
+// branches first, each group oldest first.
+func runCherryPickListReleases(formatValue string) {
+	format := parseOutputFormat(formatValue)
+	branches, err := git.ListRemoteReleaseBranches()
+	if err != nil {
+		log.Fatalf("Failed to list release branches: %v", err)
+	}
+	if len(branches) == 0 && format == output.FormatTable {
+		log.Info("No release branches found on origin")
+		return
+	}
+
+	latest, _ := git.LatestStableRelease(branches)
+
+	table := output.Table{Columns: []string{"VERSION", "BRANCH", "STABLE", "LATEST"}}
+	var suffixed []git.ReleaseBranch
+	for _, b := range branches {
+		if !b.IsStable() {
+			suffixed = append(suffixed, b)
+			continue
+		}
+		table.AddRow(b.Version(), b.Name, "true", strconv.FormatBool(b.Name == latest.Name))
+	}
+	for _, b := range suffixed {
+		table.AddRow(b.Version(), b.Name, "false", "false")
+	}
+
+	if err := output.Write(os.Stdout, format, table); err != nil {
+		log.Fatalf("Failed to write release branches: %v", err)
+	}
+}
+
 // normalizeVersion ensures the version has a 'v' prefix
 func normalizeVersion(version string) string {
 	if !strings.HasPrefix(version, "v") {
@@ -593,7 +1390,41 @@ func findNearestStableTag(commitSHA string) (string, error) {
 }
 
 // createCherryPickPR creates a pull request for cherry-picks using the GitHub CLI
-func createCherryPickPR(headBranch, baseBranch, title string, commitSHAs, commitMessages, assignees []string) (string, error) {
+func createCherryPickPR(headBranch, baseBranch, title string, commitSHAs, commitMessages, assignees, labels []string) (string, error) {
+	args := []string{
+		"pr", "create",
+		"--base", baseBranch,
+		"--head", headBranch,
+		"--title", title,
+		"--body", cherryPickPRBody(baseBranch, commitSHAs, commitMessages),
+	}
+
+	for _, label := range cherryPickPRLabels(labels) {
+		args = append(args, "--label", label)
+	}
+	for _, assignee := range assignees {
+		args = append(args, "--assignee", assignee)
+	}
+
+	// Creating a PR isn't idempotent: if gh times out after GitHub created it,
+	// a retry would fail with "already exists", so make a single attempt.
+	output, err := git.RunGH(git.GHOptions{Attempts: 1}, args...)
+	if err != nil {
+		return "", err
+	}
+
+	prURL := strings.TrimSpace(string(output))
+	return prURL, nil
+}
+
+// cherryPickPRLabels returns the labels for a cherry-pick PR: the cherry-pick
+// label followed by any --label extras.
+func cherryPickPRLabels(extra []string) []string {
+	return dedupeNonEmpty(append([]string{cherryPickPRLabel}, extra...))
+}
+
+// cherryPickPRBody returns the body of a cherry-pick PR into baseBranch.
+func cherryPickPRBody(baseBranch string, commitSHAs, commitMessages []string) string {
 	var body string
 
 	// Collect all original PR numbers for the summary
@@ -628,32 +1459,7 @@ func createCherryPickPR(headBranch, baseBranch, title string, commitSHAs, commit
 	// Add standard checklist
 	body += "\n\n"
 	body += "- [x] [Optional] Override Linear Check\n"
-
-	args := []string{
-		"pr", "create",
-		"--base", baseBranch,
-		"--head", headBranch,
-		"--title", title,
-		"--body", body,
-		"--label", cherryPickPRLabel,
-	}
-
-	for _, assignee := range assignees {
-		args = append(args, "--assignee", assignee)
-	}
-
-	cmd := exec.Command("gh", args...)
-
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("%w: %s", err, string(exitErr.Stderr))
-		}
-		return "", err
-	}
-
-	prURL := strings.TrimSpace(string(output))
-	return prURL, nil
+	return body
 }
 
 func parseCSVEnv(name string) ([]string, error) {
@@ -672,12 +1478,18 @@ func parseCSVEnv(name string) ([]string, error) {
 	return dedupeNonEmpty(values), nil
 }
 
+// resolveAssignees returns the PR assignees: --assignee if given, else
+// $CHERRY_PICK_ASSIGNEE if set (CI sets it, possibly empty), else the current
+// GitHub user.
 func resolveAssignees(cmd *cobra.Command, flagAssignees []string) ([]string, error) {
 	if cmd.Flags().Changed("assignee") {
 		return dedupeNonEmpty(flagAssignees), nil
 	}
+	if _, ok := os.LookupEnv("CHERRY_PICK_ASSIGNEE"); ok {
+		return parseCSVEnv("CHERRY_PICK_ASSIGNEE")
+	}
 
-	return parseCSVEnv("CHERRY_PICK_ASSIGNEE")
+	return []string{"@me"}, nil
 }
 
 func dedupeNonEmpty(values []string) []string {
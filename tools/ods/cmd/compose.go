@@ -4,25 +4,46 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/cache"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/prompt"
 )
 
 var validProfiles = []string{"dev", "multitenant"}
 
+// stateLockTimeout is how long a command that changes containers or the
+// database waits for another one to finish before giving up.
+const stateLockTimeout = 10 * time.Minute
+
 type ComposeOptions struct {
 	Down          bool
+	RemoveVolumes bool
+	Yes           bool
 	Wait          bool
 	ForceRecreate bool
+	Recreate      bool
 	Tag           string
+	Platform      string
 	NoEE          bool
 	Infra         bool
+	Check         bool
+	Pull          bool
+
+	// volumesConfirmed is set by callers that already ran confirmRemoveVolumes,
+	// so the summary isn't printed twice.
+	volumesConfirmed bool
 }
 
 // NewComposeCommand creates a new compose command for launching docker
@@ -59,17 +80,35 @@ Examples:
   ods compose --down
   ods compose dev --down
 
+  # Stop containers and delete their volumes (wipes the local database!)
+  ods compose --down --remove-volumes
+
   # Start without waiting for services to be healthy
   ods compose --wait=false
 
   # Force recreate containers
   ods compose --force-recreate
 
+  # Recover containers stuck in a bad state (recreate + fresh anonymous volumes)
+  ods compose --recreate
+
   # Start only infrastructure containers (no api_server, background, etc.)
   ods compose dev --infra
 
   # Use a specific image tag
-  ods compose --tag edge`,
+  ods compose --tag edge
+
+  # Run amd64 images (e.g. to reproduce an x86-only issue on Apple Silicon)
+  ods compose --platform linux/amd64
+
+  # Pull the latest edge images, then start the dev stack on them
+  ods compose dev --pull --tag edge
+
+  # Check that the profile's compose files exist without running docker
+  ods compose dev --check
+
+  # Restart a service and wait for it to be healthy again
+  ods compose restart api_server --wait`,
 		Args:      cobra.MaximumNArgs(1),
 		ValidArgs: validProfiles,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -77,16 +116,38 @@ Examples:
 			if len(args) > 0 {
 				profile = args[0]
 			}
+			if opts.RemoveVolumes && !opts.Down {
+				log.Fatal("--remove-volumes can only be used with --down")
+			}
+			if opts.Pull && opts.Down {
+				log.Fatal("--pull cannot be used with --down")
+			}
+			if opts.Check {
+				runComposeCheck(profile)
+				return
+			}
+			if opts.Pull {
+				validateProfile(profile)
+				runComposePull(profile, &PullOptions{Tag: opts.Tag, Platform: opts.Platform})
+			}
 			runCompose(profile, opts)
 		},
 	}
 
 	cmd.Flags().BoolVar(&opts.Down, "down", false, "Stop running containers instead of starting them")
+	cmd.Flags().BoolVar(&opts.RemoveVolumes, "remove-volumes", false, "With --down, also delete the project's volumes (database, index, file store). Asks for confirmation")
+	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Skip the --remove-volumes confirmation prompt")
 	cmd.Flags().BoolVar(&opts.Wait, "wait", true, "Wait for services to be healthy before returning")
 	cmd.Flags().BoolVar(&opts.ForceRecreate, "force-recreate", false, "Force recreate containers even if unchanged")
+	cmd.Flags().BoolVar(&opts.Recreate, "recreate", false, "Force recreate containers and renew their anonymous volumes (recovery from a bad state)")
 	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Set the IMAGE_TAG for docker compose (e.g. edge, v2.10.4)")
+	cmd.Flags().StringVar(&opts.Platform, "platform", defaultPlatform(), "Set DOCKER_DEFAULT_PLATFORM for pulled and built images (empty to let docker decide)")
 	cmd.Flags().BoolVar(&opts.NoEE, "no-ee", false, "Disable Enterprise Edition features (enabled by default)")
 	cmd.Flags().BoolVar(&opts.Infra, "infra", false, "Start only infrastructure containers (db, cache, search, model servers)")
+	cmd.Flags().BoolVar(&opts.Pull, "pull", false, "Pull the profile's images (honoring --tag and --platform) before starting the containers")
+	cmd.Flags().BoolVar(&opts.Check, "check", false, "Only check that the profile's compose files exist, without running docker")
+
+	cmd.AddCommand(NewComposeRestartCommand())
 
 	return cmd
 }
@@ -123,8 +184,12 @@ func composeProfiles(profile string) []string {
 }
 
 // baseArgs builds the common "docker compose -p <project> -f ... -f ...
-// --profile ..." argument prefix.
-func baseArgs(profile string) []string {
+// --profile ..." argument prefix. It fails if any of the profile's compose
+// files is missing.
+func baseArgs(profile string) ([]string, error) {
+	if err := checkComposeFiles(profile); err != nil {
+		return nil, err
+	}
 	args := []string{"compose", "-p", docker.ProjectName()}
 	for _, f := range composeFiles(profile) {
 		args = append(args, "-f", f)
@@ -132,7 +197,7 @@ func baseArgs(profile string) []string {
 	for _, p := range composeProfiles(profile) {
 		args = append(args, "--profile", p)
 	}
-	return args
+	return args, nil
 }
 
 // profileLabel returns a display label for the profile.
@@ -146,6 +211,10 @@ func profileLabel(profile string) string {
 // execDockerCompose runs a docker compose command in the correct directory with
 // optional extra environment variables.
 func execDockerCompose(args []string, extraEnv []string) {
+	if docker.DryRun() {
+		log.Infof("[DRY RUN] docker %s", strings.Join(args, " "))
+		return
+	}
 	log.Debugf("Running: docker %v", args)
 
 	dockerCmd := exec.Command("docker", args...)
@@ -162,6 +231,42 @@ func execDockerCompose(args []string, extraEnv []string) {
 	}
 }
 
+// lockDockerState takes the advisory lock that serializes ods commands which
+// change containers or the database, so two of them never interleave. A second
+// invocation waits for the first and fails if it takes too long. The lock is
+// released by the returned function, on log.Fatal, and on SIGINT/SIGTERM.
+// With --docker-dry-run nothing changes, so no lock is taken.
+func lockDockerState() func() {
+	if docker.DryRun() {
+		return func() {}
+	}
+	lockPath := filepath.Join(paths.DataDir(), "docker-state.lock")
+	unlock, err := cache.Lock(lockPath, stateLockTimeout)
+	if err != nil {
+		log.Fatalf("Another ods command (pid %s) is still changing containers or the database: %v\nWait for it to finish, or delete %s if it is no longer running.",
+			cache.LockHolder(lockPath), err, lockPath)
+	}
+
+	var once sync.Once
+	release := func() { once.Do(unlock) }
+	log.RegisterExitHandler(release)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-sigs; ok {
+			release()
+			os.Exit(130)
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(sigs)
+		release()
+	}
+}
+
 // runningServiceNames returns the names of currently running services in the
 // compose project by running "docker compose -p onyx ps --services".
 // On any error it returns nil (completions will just be empty).
@@ -189,6 +294,71 @@ func runningServiceNames() []string {
 	return services
 }
 
+// composeOutput runs a docker compose command in the compose directory and
+// returns its non-empty output lines.
+func composeOutput(args []string) ([]string, error) {
+	cmd := exec.Command("docker", args...)
+	cmd.Dir = composeDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// projectVolumeNames returns the docker volumes created for the compose project.
+func projectVolumeNames() ([]string, error) {
+	cmd := exec.Command("docker", "volume", "ls", "-q",
+		"--filter", fmt.Sprintf("label=com.docker.compose.project=%s", docker.ProjectName()))
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// confirmRemoveVolumes prints the services and volumes that "down -v" will
+// affect and asks the user to confirm. Returns false if the user declines.
+func confirmRemoveVolumes(profile string, opts *ComposeOptions) bool {
+	services := docker.InfraServiceNames()
+	if !opts.Infra {
+		args, err := baseArgs(profile)
+		if err == nil {
+			services, err = composeOutput(append(args, "ps", "-a", "--services"))
+		}
+		if err != nil {
+			log.Warnf("Failed to list compose services: %v", err)
+		}
+	}
+	volumes, err := projectVolumeNames()
+	if err != nil {
+		log.Warnf("Failed to list project volumes: %v", err)
+	}
+
+	fmt.Printf("Services: %s\n", strings.Join(services, ", "))
+	if len(volumes) == 0 {
+		fmt.Println("Volumes:  (none found)")
+	} else {
+		fmt.Println("Volumes:")
+		for _, v := range volumes {
+			fmt.Printf("  %s\n", v)
+		}
+	}
+
+	if opts.Yes {
+		return true
+	}
+	msg := fmt.Sprintf("This will stop project %q and DELETE its volumes. All local data will be lost. Continue? (yes/no): ", docker.ProjectName())
+	return prompt.Confirm(msg)
+}
+
 // envForTag returns the environment slice needed to set IMAGE_TAG, or nil.
 func envForTag(tag string) []string {
 	if tag == "" {
@@ -197,6 +367,21 @@ func envForTag(tag string) []string {
 	return []string{fmt.Sprintf("IMAGE_TAG=%s", tag)}
 }
 
+// defaultPlatform returns the docker platform matching the host architecture,
+// e.g. "linux/arm64" on Apple Silicon.
+func defaultPlatform() string {
+	return "linux/" + runtime.GOARCH
+}
+
+// envForPlatform returns the environment slice needed to set
+// DOCKER_DEFAULT_PLATFORM, or nil.
+func envForPlatform(platform string) []string {
+	if platform == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("DOCKER_DEFAULT_PLATFORM=%s", platform)}
+}
+
 // composeDir returns the path to the docker compose directory.
 func composeDir() string {
 	gitRoot, err := paths.GitRoot()
@@ -206,6 +391,23 @@ func composeDir() string {
 	return filepath.Join(gitRoot, "deployment", "docker_compose")
 }
 
+// checkComposeFiles verifies that every compose file selected by profile exists
+// under composeDir, so a changed repo layout fails with the missing file's name
+// instead of a cryptic docker error.
+func checkComposeFiles(profile string) error {
+	dir := composeDir()
+	for _, f := range composeFiles(profile) {
+		path := filepath.Join(dir, f)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("compose file %s not found (expected in %s)", f, dir)
+			}
+			return fmt.Errorf("failed to check compose file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // setEnvValue sets a key=value pair in the .env file within the compose
 // directory. If the key already exists its value is updated in place; otherwise
 // the entry is appended. The file is created if it does not exist.
@@ -259,6 +461,12 @@ func setEnvValue(key, value string) {
 // containers. EE licensing env vars are also written on startup.
 func runCompose(profile string, opts *ComposeOptions) {
 	validateProfile(profile)
+	// Build the args before touching .env so a bad layout fails before
+	// anything changes
+	args, err := baseArgs(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	if !opts.Down {
 		eeValue := "true"
@@ -281,10 +489,15 @@ func runCompose(profile string, opts *ComposeOptions) {
 		}
 	}
 
-	args := baseArgs(profile)
-
 	if opts.Down {
 		args = append(args, "down")
+		if opts.RemoveVolumes {
+			if !opts.volumesConfirmed && !confirmRemoveVolumes(profile, opts) {
+				log.Info("Aborted.")
+				return
+			}
+			args = append(args, "--volumes")
+		}
 		if opts.Infra {
 			args = append(args, docker.InfraServiceNames()...)
 		}
@@ -293,9 +506,12 @@ func runCompose(profile string, opts *ComposeOptions) {
 		if opts.Wait {
 			args = append(args, "--wait")
 		}
-		if opts.ForceRecreate {
+		if opts.ForceRecreate || opts.Recreate {
 			args = append(args, "--force-recreate")
 		}
+		if opts.Recreate {
+			args = append(args, "--renew-anon-volumes")
+		}
 		if opts.Infra {
 			args = append(args, docker.InfraServiceNames()...)
 		}
@@ -310,11 +526,32 @@ func runCompose(profile string, opts *ComposeOptions) {
 	if !opts.Down && !opts.NoEE {
 		log.Info("Enterprise Edition features enabled (use --no-ee to disable)")
 	}
-	execDockerCompose(args, envForTag(opts.Tag))
+	if !opts.Down && opts.Recreate {
+		log.Warn("Forcing recreation of all containers and renewing anonymous volumes")
+	} else if !opts.Down && opts.ForceRecreate {
+		log.Warn("Forcing recreation of all containers")
+	}
+	unlock := lockDockerState()
+	defer unlock()
+	execDockerCompose(args, append(envForTag(opts.Tag), envForPlatform(opts.Platform)...))
 
-	if opts.Down {
+	if opts.Down && opts.RemoveVolumes {
+		log.Info("Containers stopped and volumes removed successfully")
+	} else if opts.Down {
 		log.Info("Containers stopped successfully")
 	} else {
 		log.Info("Containers started successfully")
 	}
 }
+
+// runComposeCheck verifies the profile's compose files exist and reports them.
+func runComposeCheck(profile string) {
+	validateProfile(profile)
+	if err := checkComposeFiles(profile); err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range composeFiles(profile) {
+		log.Infof("Found %s", filepath.Join(composeDir(), f))
+	}
+	log.Infof("All compose files for %s configuration exist", profileLabel(profile))
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+)
+
+// ComposeRestartOptions holds options for the compose restart command.
+type ComposeRestartOptions struct {
+	Profile string
+	Wait    bool
+	Timeout time.Duration
+}
+
+// NewComposeRestartCommand creates the compose restart subcommand.
+func NewComposeRestartCommand() *cobra.Command {
+	opts := &ComposeRestartOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "restart <service>...",
+		Short: "Restart running Onyx services",
+		Long: `Restart one or more running services with docker compose restart.
+
+With --wait, block until each restarted service reports healthy again (or is
+running, for services without a healthcheck), printing its health before and
+after the restart. The command fails if a service stops or doesn't recover
+within --timeout.
+
+Examples:
+  ods compose restart api_server
+  ods compose restart api_server --wait
+  ods compose restart background --profile dev --wait --timeout 10m`,
+		Args: cobra.MinimumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return runningServiceNames(), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			runComposeRestart(args, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Profile, "profile", "", "Compose profile the services were started with (dev, multitenant)")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the restarted services to be healthy before returning")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Minute, "With --wait, how long to wait for each service")
+
+	return cmd
+}
+
+func runComposeRestart(services []string, opts *ComposeRestartOptions) {
+	validateProfile(opts.Profile)
+	base, err := baseArgs(opts.Profile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Resolve containers up front so a typo fails before anything restarts
+	containers := make(map[string]string, len(services))
+	if opts.Wait {
+		for _, service := range services {
+			ids, err := composeOutput(append(base, "ps", "-q", service))
+			if err != nil || len(ids) == 0 {
+				log.Fatalf("Service %q is not running (see: ods compose %s)", service, opts.Profile)
+			}
+			containers[service] = ids[0]
+			if status, err := docker.Health(ids[0]); err == nil {
+				log.Infof("%s: %s before restart", service, status)
+			}
+		}
+	}
+
+	unlock := lockDockerState()
+	defer unlock()
+
+	execDockerCompose(append(append(base, "restart"), services...), nil)
+
+	if !opts.Wait || docker.DryRun() {
+		return
+	}
+	for _, service := range services {
+		log.Infof("Waiting for %s to be healthy...", service)
+		status, err := docker.WaitHealthy(containers[service], opts.Timeout)
+		if err != nil {
+			log.Fatalf("%s did not come back healthy: %v (see: ods logs %s)", service, err, service)
+		}
+		log.Infof("%s: %s after restart", service, status)
+	}
+}
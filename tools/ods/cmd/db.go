@@ -12,17 +12,27 @@ func NewDBCommand() *cobra.Command {
 		Long: `Database administration commands for managing PostgreSQL and Alembic migrations.
 
 Commands include dropping/recreating databases, creating and restoring snapshots,
-and managing Alembic migrations (upgrade, downgrade, current, history).`,
+and managing Alembic migrations (upgrade, downgrade, current, history,
+revision, heads, merge, check).
+
+The PostgreSQL container is detected automatically. Set ODS_POSTGRES_CONTAINER
+to a container name to skip detection (e.g. for a custom compose project name).`,
 	}
 
 	// Add subcommands
 	cmd.AddCommand(NewDBDropCommand())
 	cmd.AddCommand(NewDBDumpCommand())
+	cmd.AddCommand(NewDBSnapshotCommand())
 	cmd.AddCommand(NewDBRestoreCommand())
 	cmd.AddCommand(NewDBUpgradeCommand())
 	cmd.AddCommand(NewDBDowngradeCommand())
 	cmd.AddCommand(NewDBCurrentCommand())
 	cmd.AddCommand(NewDBHistoryCommand())
+	cmd.AddCommand(NewDBRevisionCommand())
+	cmd.AddCommand(NewDBHeadsCommand())
+	cmd.AddCommand(NewDBMergeCommand())
+	cmd.AddCommand(NewDBCheckCommand())
+	cmd.AddCommand(NewDBWaitUpgradeCommand())
 
 	return cmd
 }
@@ -76,6 +76,9 @@ func runDBDrop(opts *DBDropOptions) {
 		}
 	}
 
+	unlock := lockDockerState()
+	defer unlock()
+
 	env := config.Env()
 
 	if opts.Schema != "" {
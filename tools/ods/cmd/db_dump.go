@@ -4,21 +4,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/alembic"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/git"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/postgres"
 )
 
 // DBDumpOptions holds options for the db dump command.
 type DBDumpOptions struct {
-	Format string
-	Schema string
-	Output string
+	Format        string
+	Schema        string
+	Output        string
+	CompressLevel int
+	LabelRevision bool
+	Gzip          bool // compress a plain dump with gzip (used by db snapshot)
 }
 
 // NewDBDumpCommand creates the db dump command.
@@ -32,28 +40,61 @@ func NewDBDumpCommand() *cobra.Command {
 
 The snapshot is saved to the specified output file, or to the default
 snapshots directory (~/.local/share/onyx-dev/snapshots/) if no file is specified.
+Default snapshot names include the current git branch so you can tell what
+work they belong to; restore accepts the name with or without its extension.
 
 Examples:
-  ods db dump                           # Creates onyx_<timestamp>.dump in snapshots dir
+  ods db dump                           # Creates <branch>-<timestamp>.dump in snapshots dir
   ods db dump mybackup.dump             # Creates mybackup.dump in snapshots dir
   ods db dump /path/to/backup.sql       # Creates backup.sql at specified path
-  ods db dump --format sql              # Creates SQL format instead of custom format`,
+  ods db dump --format plain            # Creates plain SQL instead of custom format
+  ods db dump --format directory        # Creates a directory archive (parallel restore)
+  ods db dump --compress-level 9        # Smallest file, slowest dump
+  ods db dump --label-revision          # Record the alembic revision with the snapshot
+
+Formats map to pg_dump -F:
+  custom     compressed archive (default); supports selective and parallel restore
+  plain      plain SQL script ("sql" is accepted as an alias)
+  directory  one compressed file per table; supports selective and parallel restore
+
+--compress-level (pg_dump -Z) applies to the custom and directory formats.
+
+With --label-revision, the alembic revision of the default and private
+schemas is recorded in a <snapshot>.meta.json file next to the snapshot.
+'ods db restore' then warns when a restored snapshot is behind the code's
+migration head.`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) > 0 {
 				opts.Output = args[0]
 			}
+			if format, err := postgres.NormalizeFormat(opts.Format); err == nil && format == postgres.FormatPlain && cmd.Flags().Changed("compress-level") {
+				log.Fatal("--compress-level does not apply to plain format dumps")
+			}
 			runDBDump(opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Format, "format", "custom", "Output format: 'custom' (pg_dump -Fc) or 'sql' (plain SQL)")
+	cmd.Flags().StringVar(&opts.Format, "format", postgres.FormatCustom, "Output format: 'custom' (pg_dump -Fc), 'plain' (plain SQL, alias 'sql'), or 'directory' (pg_dump -Fd)")
 	cmd.Flags().StringVar(&opts.Schema, "schema", "", "Dump only a specific schema")
+	cmd.Flags().BoolVar(&opts.LabelRevision, "label-revision", false, "Record the current alembic revision of each schema in the snapshot's metadata")
+	cmd.Flags().IntVar(&opts.CompressLevel, "compress-level", postgres.DefaultCompressLevel, "Compression level 0-9 for custom and directory formats (0 = none, 9 = smallest)")
 
 	return cmd
 }
 
 func runDBDump(opts *DBDumpOptions) {
+	format, err := postgres.NormalizeFormat(opts.Format)
+	if err != nil {
+		log.Fatalf("Invalid --format: %v", err)
+	}
+	opts.Format = format
+	// Validate the level before touching the database
+	compressArgs, err := postgres.CompressArgs(opts.Format, opts.CompressLevel)
+	if err != nil {
+		log.Fatalf("Invalid --compress-level: %v", err)
+	}
+
 	// Find PostgreSQL container.
 	container, err := docker.FindPostgresContainer(docker.ProjectName())
 	if err != nil {
@@ -76,12 +117,18 @@ func runDBDump(opts *DBDumpOptions) {
 
 	// Build pg_dump arguments.
 	args := config.PgDumpArgs(opts.Format)
+	args = append(args, compressArgs...)
+	if opts.Gzip && opts.Format == postgres.FormatPlain {
+		// pg_dump gzips the whole script when a plain dump is compressed
+		args = append(args, "-Z", strconv.Itoa(opts.CompressLevel))
+	}
 	if opts.Schema != "" {
 		args = append(args, "-n", opts.Schema)
 	}
 
-	// Create a temporary file in the container.
+	// Create a temporary file (or directory, for -Fd) in the container.
 	containerTmpFile := "/tmp/onyx_dump_tmp"
+	_ = docker.Exec(container, "rm", "-rf", containerTmpFile)
 	args = append(args, "-f", containerTmpFile)
 
 	// Run pg_dump in container.
@@ -97,27 +144,65 @@ func runDBDump(opts *DBDumpOptions) {
 	}
 
 	// Clean up temporary file in container.
-	_ = docker.Exec(container, "rm", "-f", containerTmpFile)
+	_ = docker.Exec(container, "rm", "-rf", containerTmpFile)
 
 	// Get file size for info.
-	if info, err := os.Stat(outputPath); err == nil {
-		log.Infof("Dump completed successfully (%s)", humanizeBytes(info.Size()))
+	if size, err := pathSize(outputPath); err == nil {
+		log.Infof("Dump completed successfully (%s)", humanizeBytes(size))
 	} else {
 		log.Info("Dump completed successfully")
 	}
+
+	if opts.LabelRevision && docker.DryRun() {
+		log.Infof("[DRY RUN] Would label %s with the current alembic revisions", outputPath)
+	} else if opts.LabelRevision {
+		labelSnapshotRevision(outputPath)
+	}
+}
+
+// labelSnapshotRevision records the database's alembic revisions in the
+// snapshot's metadata. The dump has already succeeded, so failures only warn.
+func labelSnapshotRevision(snapshotPath string) {
+	meta := postgres.SnapshotMeta{CreatedAt: time.Now().UTC(), Revisions: map[string]string{}}
+	for _, schema := range []alembic.Schema{alembic.SchemaDefault, alembic.SchemaPrivate} {
+		revision, err := alembic.CurrentRevision(schema)
+		if err != nil {
+			log.Warnf("Could not read the %s schema revision: %v", schema, err)
+			continue
+		}
+		if id := alembic.RevisionID(revision); id != "" {
+			meta.Revisions[string(schema)] = id
+			log.Infof("Labeled snapshot with %s schema revision %s", schema, id)
+		}
+	}
+	if err := postgres.WriteSnapshotMeta(snapshotPath, meta); err != nil {
+		log.Warnf("Failed to label snapshot: %v", err)
+	}
+}
+
+// pathSize returns the size of a file, or the total size of a directory's files.
+func pathSize(path string) (int64, error) {
+	var total int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
 }
 
 // determineOutputPath determines the output file path based on options.
 func determineOutputPath(output string, format string) string {
-	ext := ".dump"
-	if format == "sql" {
-		ext = ".sql"
-	}
+	ext := postgres.FormatExtension(format)
 
 	if output == "" {
-		// Generate default filename with timestamp.
+		// Generate default filename from the current branch and a timestamp.
 		timestamp := time.Now().Format("20060102_150405")
-		filename := fmt.Sprintf("onyx_%s%s", timestamp, ext)
+		filename := fmt.Sprintf("%s-%s%s", snapshotPrefix(), timestamp, ext)
 		return filepath.Join(paths.SnapshotsDir(), filename)
 	}
 
@@ -129,6 +214,24 @@ func determineOutputPath(output string, format string) string {
 	return output
 }
 
+// unsafeSnapshotChars matches characters that shouldn't appear in snapshot filenames.
+var unsafeSnapshotChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// snapshotPrefix returns the current git branch made filename-safe
+// (e.g. "feature/login" → "feature-login"), or "onyx" when there is no branch
+// (detached HEAD, not a git checkout).
+func snapshotPrefix() string {
+	branch, err := git.GetCurrentBranch()
+	if err != nil || branch == "" {
+		return "onyx"
+	}
+	name := strings.Trim(unsafeSnapshotChars.ReplaceAllString(branch, "-"), "-.")
+	if name == "" {
+		return "onyx"
+	}
+	return name
+}
+
 // humanizeBytes converts bytes to a human-readable string.
 func humanizeBytes(bytes int64) string {
 	const unit = 1024
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -9,7 +13,10 @@ import (
 
 // MigrateOptions holds common options for migration commands.
 type MigrateOptions struct {
-	Schema string
+	Schema     string
+	Step       int
+	AllSchemas bool
+	SQL        bool
 }
 
 // getAlembicSchema converts the schema flag value to alembic.Schema.
@@ -25,6 +32,22 @@ func getAlembicSchema(schema string) (alembic.Schema, bool) {
 	}
 }
 
+// stepRevision converts --step into alembic's relative revision syntax
+// ("+N" for upgrades, "-N" for downgrades). It rejects a step that is zero or
+// combined with an explicit revision.
+func stepRevision(step int, upgrade bool, args []string) (string, error) {
+	if len(args) > 0 {
+		return "", fmt.Errorf("--step cannot be combined with a revision argument")
+	}
+	if step <= 0 {
+		return "", fmt.Errorf("--step must be a positive integer, got %d", step)
+	}
+	if upgrade {
+		return fmt.Sprintf("+%d", step), nil
+	}
+	return fmt.Sprintf("-%d", step), nil
+}
+
 // NewDBUpgradeCommand creates the db upgrade command.
 func NewDBUpgradeCommand() *cobra.Command {
 	opts := &MigrateOptions{}
@@ -36,6 +59,15 @@ func NewDBUpgradeCommand() *cobra.Command {
 
 If no revision is specified, upgrades to 'head' (latest revision).
 
+Use --all-schemas on a fresh environment to upgrade the default schema and
+then the private (multi-tenant) schema to head in one go.
+
+Use --sql to print the SQL the upgrade would run instead of applying it
+(alembic offline mode), e.g. for review before a production deploy. The
+database is not touched and doesn't need to be running. Without a database
+alembic can't tell the current revision, so --step and relative revisions
+(+N) can't be used with --sql; pass an explicit target or from:to range.
+
 The command automatically detects the PostgreSQL container IP if POSTGRES_HOST
 is not set, so it works even when the port isn't exposed to localhost.
 
@@ -50,19 +82,41 @@ Examples:
   ods db upgrade                    # Upgrade to latest
   ods db upgrade head               # Same as above
   ods db upgrade +1                 # Upgrade one revision
+  ods db upgrade --step 1           # Same as above
   ods db upgrade abc123             # Upgrade to specific revision
-  ods db upgrade --schema private   # Upgrade private schema (multi-tenant)`,
+  ods db upgrade --schema private   # Upgrade private schema (multi-tenant)
+  ods db upgrade --all-schemas      # Upgrade default, then private, to head
+  ods db upgrade head --sql > up.sql  # Print the SQL without applying it`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.AllSchemas {
+				if len(args) > 0 || cmd.Flags().Changed("step") || cmd.Flags().Changed("schema") || opts.SQL {
+					log.Fatal("--all-schemas upgrades every schema to head and cannot be combined with a revision, --step, --schema, or --sql")
+				}
+				runDBUpgradeAllSchemas()
+				return
+			}
 			revision := "head"
 			if len(args) > 0 {
 				revision = args[0]
 			}
+			if opts.SQL && (cmd.Flags().Changed("step") || strings.HasPrefix(revision, "+")) {
+				log.Fatal("--sql can't tell the current revision, so it cannot be combined with --step or a relative revision")
+			}
+			if cmd.Flags().Changed("step") {
+				var err error
+				if revision, err = stepRevision(opts.Step, true, args); err != nil {
+					log.Fatal(err)
+				}
+			}
 			runDBUpgrade(revision, opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to migrate: 'default' or 'private' (multi-tenant)")
+	cmd.Flags().IntVar(&opts.Step, "step", 0, "Apply exactly this many migrations forward instead of upgrading to a revision")
+	cmd.Flags().BoolVar(&opts.AllSchemas, "all-schemas", false, "Upgrade the default and private schemas to head, stopping on the first failure")
+	cmd.Flags().BoolVar(&opts.SQL, "sql", false, "Print the upgrade SQL instead of applying it (offline mode)")
 
 	return cmd
 }
@@ -73,11 +127,19 @@ func runDBUpgrade(revision string, opts *MigrateOptions) {
 		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
 	}
 
-	log.Infof("Upgrading database to revision: %s", revision)
 	if schema == alembic.SchemaPrivate {
 		log.Info("Using schema: private (schema_private)")
 	}
 
+	if opts.SQL {
+		log.Infof("Generating upgrade SQL for revision: %s", revision)
+		if err := alembic.UpgradeSQL(revision, schema); err != nil {
+			log.Fatalf("Failed to generate upgrade SQL: %v", err)
+		}
+		return
+	}
+
+	log.Infof("Upgrading database to revision: %s", revision)
 	if err := alembic.Upgrade(revision, schema); err != nil {
 		log.Fatalf("Failed to upgrade database: %v", err)
 	}
@@ -85,28 +147,114 @@ func runDBUpgrade(revision string, opts *MigrateOptions) {
 	log.Info("Upgrade completed successfully")
 }
 
+// runDBUpgradeAllSchemas upgrades the default schema, then the private
+// schema, to head.
+func runDBUpgradeAllSchemas() {
+	upgradeAllSchemasToHead(1, 0, false)
+	log.Info("Upgrade completed successfully")
+}
+
+// upgradeAllSchemasToHead upgrades the default schema, then the private
+// schema, to head, exiting on failure. Each upgrade is attempted up to retries
+// times, retryDelay apart. With reportRevisions, each schema's revision is
+// logged before and after its upgrade.
+func upgradeAllSchemasToHead(retries int, retryDelay time.Duration, reportRevisions bool) {
+	for _, schema := range []alembic.Schema{alembic.SchemaDefault, alembic.SchemaPrivate} {
+		var before string
+		if reportRevisions {
+			before = currentRevisionOrWarn(schema)
+			log.Infof("Upgrading %s schema to head (at %s)...", schema, revisionLabel(before))
+		} else {
+			log.Infof("Upgrading %s schema to head...", schema)
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := alembic.Upgrade("head", schema)
+			if err == nil {
+				break
+			}
+			if attempt >= retries {
+				if retries > 1 {
+					log.Fatalf("Failed to upgrade %s schema after %d attempt(s): %v", schema, attempt, err)
+				}
+				log.Fatalf("Failed to upgrade %s schema: %v", schema, err)
+			}
+			log.Warnf("Upgrade of %s schema failed (attempt %d/%d), retrying in %s: %v", schema, attempt, retries, retryDelay, err)
+			time.Sleep(retryDelay)
+		}
+
+		if reportRevisions {
+			after := currentRevisionOrWarn(schema)
+			log.Infof("%s schema: %s -> %s", schema, revisionLabel(before), revisionLabel(after))
+		} else {
+			log.Infof("%s schema upgraded", schema)
+		}
+	}
+}
+
+// currentRevisionOrWarn returns the schema's current revision, or "" with a
+// warning when it can't be read.
+func currentRevisionOrWarn(schema alembic.Schema) string {
+	revision, err := alembic.CurrentRevision(schema)
+	if err != nil {
+		log.Warnf("Failed to get current %s schema revision: %v", schema, err)
+		return ""
+	}
+	return revision
+}
+
+// revisionLabel returns a display label for an alembic revision.
+func revisionLabel(revision string) string {
+	if revision == "" {
+		return "(none)"
+	}
+	return revision
+}
+
 // NewDBDowngradeCommand creates the db downgrade command.
 func NewDBDowngradeCommand() *cobra.Command {
 	opts := &MigrateOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "downgrade <revision>",
+		Use:   "downgrade [revision]",
 		Short: "Rollback Alembic migrations",
 		Long: `Rollback Alembic migrations to a previous revision.
 
+Use --sql to print the SQL the downgrade would run instead of applying it
+(alembic offline mode). Offline downgrades need an explicit from:to revision
+range, so --step and relative revisions can't be used with --sql.
+
 Examples:
   ods db downgrade -1               # Downgrade one revision
   ods db downgrade -2               # Downgrade two revisions
+  ods db downgrade --step 1         # Downgrade one revision
   ods db downgrade base             # Downgrade to initial state
   ods db downgrade abc123           # Downgrade to specific revision
-  ods db downgrade --schema private # Downgrade private schema`,
-		Args: cobra.ExactArgs(1),
+  ods db downgrade --schema private # Downgrade private schema
+  ods db downgrade def456:abc123 --sql  # Print the SQL without applying it`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.SQL && cmd.Flags().Changed("step") {
+				log.Fatal("--sql needs an explicit from:to revision range and cannot be combined with --step")
+			}
+			if cmd.Flags().Changed("step") {
+				revision, err := stepRevision(opts.Step, false, args)
+				if err != nil {
+					log.Fatal(err)
+				}
+				runDBDowngrade(revision, opts)
+				return
+			}
+			if len(args) == 0 {
+				log.Fatal("Must provide a revision or --step")
+			}
 			runDBDowngrade(args[0], opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to migrate: 'default' or 'private' (multi-tenant)")
+	cmd.Flags().IntVar(&opts.Step, "step", 0, "Roll back exactly this many migrations instead of downgrading to a revision")
+	cmd.Flags().BoolVar(&opts.SQL, "sql", false, "Print the downgrade SQL instead of applying it (offline mode; needs from:to)")
 
 	return cmd
 }
@@ -117,11 +265,22 @@ func runDBDowngrade(revision string, opts *MigrateOptions) {
 		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
 	}
 
-	log.Infof("Downgrading database to revision: %s", revision)
 	if schema == alembic.SchemaPrivate {
 		log.Info("Using schema: private (schema_private)")
 	}
 
+	if opts.SQL {
+		if !strings.Contains(revision, ":") {
+			log.Fatalf("--sql needs a from:to revision range (e.g. def456:abc123), got %q", revision)
+		}
+		log.Infof("Generating downgrade SQL for: %s", revision)
+		if err := alembic.DowngradeSQL(revision, schema); err != nil {
+			log.Fatalf("Failed to generate downgrade SQL: %v", err)
+		}
+		return
+	}
+
+	log.Infof("Downgrading database to revision: %s", revision)
 	if err := alembic.Downgrade(revision, schema); err != nil {
 		log.Fatalf("Failed to downgrade database: %v", err)
 	}
@@ -170,6 +329,7 @@ func runDBCurrent(opts *MigrateOptions) {
 type HistoryOptions struct {
 	MigrateOptions
 	Verbose bool
+	Range   string
 }
 
 // NewDBHistoryCommand creates the db history command.
@@ -181,10 +341,17 @@ func NewDBHistoryCommand() *cobra.Command {
 		Short: "Show Alembic migration history",
 		Long: `Show the Alembic migration history.
 
+Use --range to show only part of the history. It takes alembic's
+start:end syntax; either side may be omitted, and relative revisions
+such as -5 or current are allowed.
+
 Examples:
   ods db history
   ods db history --verbose
-  ods db history --schema private`,
+  ods db history --schema private
+  ods db history --range -10:            # The 10 most recent migrations
+  ods db history --range current:heads   # Migrations not yet applied
+  ods db history --range abc123:def456 -v`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runDBHistory(opts)
 		},
@@ -192,6 +359,7 @@ Examples:
 
 	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to check: 'default' or 'private' (multi-tenant)")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show verbose output")
+	cmd.Flags().StringVar(&opts.Range, "range", "", "Only show revisions in this range (start:end, e.g. -10: or current:heads)")
 
 	return cmd
 }
@@ -202,11 +370,201 @@ func runDBHistory(opts *HistoryOptions) {
 		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
 	}
 
+	if opts.Range != "" && !strings.Contains(opts.Range, ":") {
+		log.Fatalf("Invalid --range %q: expected start:end (e.g. -10: or abc123:heads)", opts.Range)
+	}
+
 	if schema == alembic.SchemaPrivate {
 		log.Info("Showing history for schema: private (schema_private)")
 	}
 
-	if err := alembic.History(schema, opts.Verbose); err != nil {
+	if err := alembic.History(schema, opts.Verbose, opts.Range); err != nil {
 		log.Fatalf("Failed to get migration history: %v", err)
 	}
 }
+
+// RevisionOptions holds options for the revision command.
+type RevisionOptions struct {
+	MigrateOptions
+	Message      string
+	Autogenerate bool
+}
+
+// NewDBRevisionCommand creates the db revision command.
+func NewDBRevisionCommand() *cobra.Command {
+	opts := &RevisionOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "revision",
+		Short: "Create a new Alembic migration",
+		Long: `Create a new Alembic migration script in the backend checkout.
+
+Use --autogenerate to have alembic fill in the migration by comparing the
+models with the database; review the generated script before committing it.
+Autogenerate needs the database to be reachable from the host (the dev
+compose file exposes port 5432), since alembic always runs locally here so
+the script is written to your checkout.
+
+Examples:
+  ods db revision -m "add user preferences table"
+  ods db revision -m "add user preferences table" --autogenerate
+  ods db revision -m "add tenant column" --autogenerate --schema private`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBRevision(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Migration message (required)")
+	cmd.Flags().BoolVar(&opts.Autogenerate, "autogenerate", false, "Generate the migration from model changes")
+	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to create the migration for: 'default' or 'private' (multi-tenant)")
+	_ = cmd.MarkFlagRequired("message")
+
+	return cmd
+}
+
+func runDBRevision(opts *RevisionOptions) {
+	schema, valid := getAlembicSchema(opts.Schema)
+	if !valid {
+		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
+	}
+	if strings.TrimSpace(opts.Message) == "" {
+		log.Fatal("--message must not be empty")
+	}
+
+	if schema == alembic.SchemaPrivate {
+		log.Info("Using schema: private (schema_private)")
+	}
+
+	if err := alembic.Revision(opts.Message, opts.Autogenerate, schema); err != nil {
+		log.Fatalf("Failed to create revision: %v", err)
+	}
+}
+
+// NewDBHeadsCommand creates the db heads command.
+func NewDBHeadsCommand() *cobra.Command {
+	opts := &MigrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "heads",
+		Short: "Show Alembic head revisions",
+		Long: `Show the head revisions of the Alembic migration scripts.
+
+More than one head means two migration branches have landed; combine them
+with 'ods db merge'.
+
+Examples:
+  ods db heads
+  ods db heads --schema private`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBHeads(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to check: 'default' or 'private' (multi-tenant)")
+
+	return cmd
+}
+
+func runDBHeads(opts *MigrateOptions) {
+	schema, valid := getAlembicSchema(opts.Schema)
+	if !valid {
+		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
+	}
+
+	if schema == alembic.SchemaPrivate {
+		log.Info("Showing heads for schema: private (schema_private)")
+	}
+
+	if err := alembic.Heads(schema); err != nil {
+		log.Fatalf("Failed to get head revisions: %v", err)
+	}
+}
+
+// MergeOptions holds options for the merge command.
+type MergeOptions struct {
+	MigrateOptions
+	Message string
+}
+
+// NewDBMergeCommand creates the db merge command.
+func NewDBMergeCommand() *cobra.Command {
+	opts := &MergeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "merge <revision> <revision>...",
+		Short: "Merge Alembic heads into one revision",
+		Long: `Create a migration that merges two or more revisions, usually the heads
+listed by 'ods db heads', so the migration history is linear again.
+
+Examples:
+  ods db merge abc123 def456 -m "merge heads"
+  ods db merge abc123 def456 --schema private`,
+		Args: cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBMerge(args, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Merge migration message")
+	cmd.Flags().StringVar(&opts.Schema, "schema", "default", "Schema to merge: 'default' or 'private' (multi-tenant)")
+
+	return cmd
+}
+
+func runDBMerge(revisions []string, opts *MergeOptions) {
+	schema, valid := getAlembicSchema(opts.Schema)
+	if !valid {
+		log.Fatalf("Invalid schema: %s (must be 'default' or 'private')", opts.Schema)
+	}
+
+	log.Infof("Merging revisions: %s", strings.Join(revisions, ", "))
+	if schema == alembic.SchemaPrivate {
+		log.Info("Using schema: private (schema_private)")
+	}
+
+	if err := alembic.Merge(revisions, opts.Message, schema); err != nil {
+		log.Fatalf("Failed to merge revisions: %v", err)
+	}
+}
+
+// NewDBCheckCommand creates the db check command.
+func NewDBCheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Fail if migrations are pending or models are out of sync",
+		Long: `Check the default and private schemas for migration problems, for use in
+pre-commit hooks and CI. A schema fails the check if:
+  - the database's current revision is behind the migration head, or
+  - alembic check finds model changes that no migration covers.
+
+Both schemas are always checked, and each failing schema is reported. The
+command exits non-zero if any schema fails.
+
+Examples:
+  ods db check`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBCheck()
+		},
+	}
+
+	return cmd
+}
+
+func runDBCheck() {
+	var failed []string
+	for _, schema := range []alembic.Schema{alembic.SchemaDefault, alembic.SchemaPrivate} {
+		log.Infof("Checking %s schema...", schema)
+		if err := alembic.Check(schema); err != nil {
+			log.Errorf("%s schema: %v", schema, err)
+			failed = append(failed, string(schema))
+			continue
+		}
+		log.Infof("%s schema is up to date", schema)
+	}
+	if len(failed) > 0 {
+		log.Fatalf("Migration check failed for schema(s): %s", strings.Join(failed, ", "))
+	}
+}
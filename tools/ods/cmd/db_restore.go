@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/alembic"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/postgres"
@@ -23,6 +26,10 @@ type DBRestoreOptions struct {
 	Yes         bool
 	Clean       bool
 	FetchSeeded bool
+	Format      string
+	Tables      []string
+	Jobs        int
+	Upgrade     bool
 }
 
 // NewDBRestoreCommand creates the db restore command.
@@ -34,21 +41,41 @@ func NewDBRestoreCommand() *cobra.Command {
 		Short: "Restore a database snapshot",
 		Long: `Restore a database snapshot using pg_restore or psql.
 
-The format is automatically detected based on file extension:
-  - .dump files: restored with pg_restore (custom format)
-  - .sql files: restored with psql (plain SQL)
+The format is detected from the snapshot itself (override with --format):
+  - directories: restored with pg_restore (directory format)
+  - files starting with the PGDMP header: restored with pg_restore (custom format)
+  - gzip-compressed files (e.g. from 'ods db snapshot'): gunzipped, then
+    restored with psql (plain SQL)
+  - anything else: restored with psql (plain SQL)
 
 If just a filename is provided (without path), the file is looked up
 in the default snapshots directory (~/.local/share/onyx-dev/snapshots/).
 
+Use --table to restore a single table (custom or directory snapshots only).
+The table must appear in the snapshot's table of contents; combine with
+--clean to drop and recreate just that table.
+
+Use --jobs to run pg_restore with several parallel workers (custom or
+directory snapshots only), which is much faster for large databases.
+
 Use --fetch-seeded to download and restore a pre-seeded database snapshot
 from S3 (requires network access or AWS credentials).
 
+Use --upgrade to run alembic upgrade head on the default and private schemas
+after restoring, so a snapshot taken on older code is usable right away. The
+revision of each schema is reported before and after the upgrade. Without
+--upgrade, a snapshot dumped with --label-revision is checked against the
+code's migration head and a warning is printed if they differ.
+
 Examples:
   ods db restore mybackup.dump           # Restores from snapshots dir
+  ods db restore before-upgrade          # Restores before-upgrade.sql.gz from snapshots dir
   ods db restore /path/to/backup.sql     # Restores from absolute path
   ods db restore backup.dump --clean     # Drop objects before restoring
-  ods db restore --fetch-seeded          # Download and restore seeded snapshot`,
+  ods db restore backup.dump --table user --clean  # Restore only the user table
+  ods db restore backup.dir --jobs 8     # Parallel restore of a directory snapshot
+  ods db restore --fetch-seeded          # Download and restore seeded snapshot
+  ods db restore old.dump --upgrade      # Restore, then migrate to head`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			if opts.FetchSeeded {
@@ -66,6 +93,10 @@ Examples:
 	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Skip confirmation prompt")
 	cmd.Flags().BoolVar(&opts.Clean, "clean", false, "Drop database objects before restoring")
 	cmd.Flags().BoolVar(&opts.FetchSeeded, "fetch-seeded", false, "Download and restore the seeded database snapshot from S3")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Snapshot format: 'custom', 'plain', or 'directory' (default: auto-detect)")
+	cmd.Flags().StringArrayVar(&opts.Tables, "table", nil, "Restore only this table (\"table\" or \"schema.table\"; repeatable)")
+	cmd.Flags().IntVarP(&opts.Jobs, "jobs", "j", 0, "Number of parallel pg_restore jobs (custom or directory format only)")
+	cmd.Flags().BoolVar(&opts.Upgrade, "upgrade", false, "Run alembic upgrade head on both schemas after restoring")
 
 	return cmd
 }
@@ -83,12 +114,12 @@ func completeSnapshotFiles(cmd *cobra.Command, args []string, toComplete string)
 	entries, err := os.ReadDir(snapshotsDir)
 	if err == nil {
 		for _, entry := range entries {
-			if entry.IsDir() {
+			name := entry.Name()
+			if entry.IsDir() && !strings.HasSuffix(name, ".dir") {
 				continue
 			}
-			name := entry.Name()
-			// Only suggest .dump and .sql files.
-			if strings.HasSuffix(name, ".dump") || strings.HasSuffix(name, ".sql") {
+			// Only suggest .dump, .sql, and .sql.gz files, and .dir directory archives.
+			if strings.HasSuffix(name, ".dump") || strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, gzipSnapshotExt) || strings.HasSuffix(name, ".dir") {
 				if strings.HasPrefix(name, toComplete) {
 					completions = append(completions, name)
 				}
@@ -152,25 +183,68 @@ func runDBRestore(input string, opts *DBRestoreOptions) {
 		}
 	}
 
-	// Detect format from extension.
-	isCustomFormat := strings.HasSuffix(strings.ToLower(inputPath), ".dump")
+	unlock := lockDockerState()
+	defer unlock()
+
+	// Detect format from the snapshot contents unless overridden.
+	format, err := resolveRestoreFormat(inputPath, opts.Format)
+	if err != nil {
+		log.Fatalf("Failed to determine snapshot format: %v", err)
+	}
+	gzipped, err := postgres.IsGzipped(inputPath)
+	if err != nil {
+		log.Fatalf("Failed to read snapshot: %v", err)
+	}
+	if gzipped {
+		// Only plain SQL dumps are gzipped as a whole; archives compress internally
+		if opts.Format != "" && format != postgres.FormatPlain {
+			log.Fatalf("Gzip-compressed snapshots can only be restored as plain SQL, not %s", format)
+		}
+		format = postgres.FormatPlain
+	}
+	if len(opts.Tables) > 0 && format == postgres.FormatPlain {
+		log.Fatal("--table requires a custom or directory format snapshot (plain SQL dumps cannot be restored selectively)")
+	}
+	if opts.Jobs < 0 {
+		log.Fatalf("--jobs must be positive, got %d", opts.Jobs)
+	}
+	if opts.Jobs > 1 && format == postgres.FormatPlain {
+		log.Fatal("--jobs requires a custom or directory format snapshot (plain SQL dumps are restored with psql)")
+	}
 
-	log.Infof("Restoring database '%s' from: %s", config.Database, inputPath)
+	formatLabel := format
+	if gzipped {
+		formatLabel += ", gzip"
+	}
+	log.Infof("Restoring database '%s' from: %s (%s format)", config.Database, inputPath, formatLabel)
 
-	// Copy file to container.
+	// Copy file (or directory archive) to container.
 	containerTmpFile := "/tmp/onyx_restore_tmp"
+	_ = docker.Exec(container, "rm", "-rf", containerTmpFile)
 	if err := docker.CopyToContainer(container, inputPath, containerTmpFile); err != nil {
 		log.Fatalf("Failed to copy file to container: %v", err)
 	}
 
 	env := config.Env()
 
-	if isCustomFormat {
-		// Use pg_restore for custom format.
+	if format != postgres.FormatPlain {
+		// Use pg_restore for custom and directory formats.
 		args := config.PgRestoreArgs()
 		if opts.Clean {
 			args = append(args, "--clean", "--if-exists")
 		}
+		if opts.Jobs > 1 {
+			log.Infof("Restoring with %d parallel jobs", opts.Jobs)
+			args = append(args, "-j", strconv.Itoa(opts.Jobs))
+		}
+		if len(opts.Tables) > 0 {
+			tableArgs, err := restoreTableArgs(container, containerTmpFile, opts.Tables)
+			if err != nil {
+				_ = docker.Exec(container, "rm", "-rf", containerTmpFile)
+				log.Fatalf("Cannot restore selected tables: %v", err)
+			}
+			args = append(args, tableArgs...)
+		}
 		args = append(args, containerTmpFile)
 
 		restoreArgs := append([]string{"pg_restore"}, args...)
@@ -179,9 +253,19 @@ func runDBRestore(input string, opts *DBRestoreOptions) {
 			log.Warnf("pg_restore completed with warnings or errors: %v", err)
 		}
 	} else {
+		sqlFile := containerTmpFile
+		if gzipped {
+			sqlFile = containerTmpFile + ".sql"
+			if err := docker.Exec(container, "sh", "-c", fmt.Sprintf("gunzip -c %s > %s", containerTmpFile, sqlFile)); err != nil {
+				_ = docker.Exec(container, "rm", "-rf", containerTmpFile, sqlFile)
+				log.Fatalf("Failed to decompress snapshot: %v", err)
+			}
+			defer func() { _ = docker.Exec(container, "rm", "-f", sqlFile) }()
+		}
+
 		// Use psql for SQL format.
 		args := config.PsqlArgs()
-		args = append(args, "-f", containerTmpFile)
+		args = append(args, "-f", sqlFile)
 
 		psqlArgs := append([]string{"psql"}, args...)
 		if err := docker.ExecWithEnv(container, env, psqlArgs...); err != nil {
@@ -190,9 +274,83 @@ func runDBRestore(input string, opts *DBRestoreOptions) {
 	}
 
 	// Clean up temporary file in container.
-	_ = docker.Exec(container, "rm", "-f", containerTmpFile)
+	_ = docker.Exec(container, "rm", "-rf", containerTmpFile)
 
 	log.Info("Restore completed successfully")
+
+	if opts.Upgrade {
+		upgradeAllSchemasToHead(1, 0, true)
+	} else {
+		warnIfBehindHead(inputPath)
+	}
+}
+
+// warnIfBehindHead compares the revisions recorded in a snapshot's metadata
+// with the code's migration heads and warns when they differ.
+func warnIfBehindHead(snapshotPath string) {
+	meta, err := postgres.ReadSnapshotMeta(snapshotPath)
+	if err != nil {
+		log.Warnf("Ignoring snapshot metadata: %v", err)
+		return
+	}
+	if meta == nil || len(meta.Revisions) == 0 {
+		return
+	}
+	for _, schema := range []alembic.Schema{alembic.SchemaDefault, alembic.SchemaPrivate} {
+		revision, ok := meta.Revisions[string(schema)]
+		if !ok {
+			continue
+		}
+		heads, err := alembic.HeadRevisions(schema)
+		if err != nil {
+			log.Debugf("Could not read %s schema heads: %v", schema, err)
+			continue
+		}
+		if !slices.Contains(heads, revision) {
+			log.Warnf("Restored %s schema is at revision %s, but the code's head is %s. Run 'ods db upgrade' (or restore with --upgrade).",
+				schema, revision, strings.Join(heads, ", "))
+		}
+	}
+}
+
+// restoreTableArgs validates that each requested table is in the archive's
+// table of contents and returns the matching pg_restore -n/-t arguments.
+func restoreTableArgs(container, archivePath string, tables []string) ([]string, error) {
+	toc, err := docker.ExecOutput(container, "pg_restore", "-l", archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshot contents: %w", err)
+	}
+	available := postgres.TOCTables(toc)
+
+	var args []string
+	schemas := map[string]bool{}
+	for _, name := range tables {
+		matches := postgres.FindTOCTable(available, name)
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("table %q not found in snapshot", name)
+		case 1:
+		default:
+			return nil, fmt.Errorf("table %q is ambiguous (%s); qualify it with a schema", name, strings.Join(matches, ", "))
+		}
+		schema, table := postgres.SplitTableName(matches[0])
+		if !schemas[schema] {
+			schemas[schema] = true
+			args = append(args, "-n", schema)
+		}
+		args = append(args, "-t", table)
+		log.Infof("Restoring table %s", matches[0])
+	}
+	return args, nil
+}
+
+// resolveRestoreFormat returns the --format override if given, otherwise the
+// format detected from the snapshot on disk.
+func resolveRestoreFormat(inputPath, override string) (string, error) {
+	if override != "" {
+		return postgres.NormalizeFormat(override)
+	}
+	return postgres.DetectFormat(inputPath)
 }
 
 // resolveInputPath resolves the input file path.
@@ -208,6 +366,13 @@ func resolveInputPath(input string) string {
 		return snapshotPath
 	}
 
+	// Accept snapshot names without their extension (e.g. "main-20250101_120000").
+	for _, ext := range []string{".dump", ".sql", gzipSnapshotExt, ".dir"} {
+		if _, err := os.Stat(snapshotPath + ext); err == nil {
+			return snapshotPath + ext
+		}
+	}
+
 	// Check if file exists in current directory.
 	if _, err := os.Stat(input); err == nil {
 		absPath, _ := filepath.Abs(input)
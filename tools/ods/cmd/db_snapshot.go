@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/alembic"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/output"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/postgres"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/prompt"
+)
+
+// gzipSnapshotExt is the extension of gzip-compressed plain SQL snapshots.
+const gzipSnapshotExt = ".sql.gz"
+
+// DBSnapshotOptions holds options for the db snapshot command.
+type DBSnapshotOptions struct {
+	Name   string
+	Schema string
+	List   bool
+	Delete string
+	Prune  bool
+	Keep   int
+	Yes    bool
+	Format string
+}
+
+// NewDBSnapshotCommand creates the db snapshot command.
+func NewDBSnapshotCommand() *cobra.Command {
+	opts := &DBSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save a compressed SQL snapshot to the snapshots directory",
+		Long: `Save a gzip-compressed plain SQL snapshot of the database to the
+snapshots directory (~/.local/share/onyx-dev/snapshots/).
+
+Snapshots are named <branch>-<timestamp>.sql.gz unless --name is given.
+The path of the snapshot is printed on success. Restore it with
+'ods db restore <name>'.
+
+Use 'ods db dump' for custom or directory format archives, which support
+selective and parallel restore.
+
+Use --list to show the snapshots directory, newest first, with each
+snapshot's size, age, and labeled revision. Use --delete to remove one
+snapshot, or --prune to remove all but the newest --keep snapshots.
+With --list --format json, each snapshot is an object with the keys name,
+size, created, age, and revision.
+
+Examples:
+  ods db snapshot                       # Creates <branch>-<timestamp>.sql.gz
+  ods db snapshot --name before-upgrade # Creates before-upgrade.sql.gz
+  ods db snapshot --schema public       # Snapshot only the public schema
+  ods db snapshot --list                # List snapshots
+  ods db snapshot --list --format json  # Machine-readable list
+  ods db snapshot --delete before-upgrade
+  ods db snapshot --prune --keep 3      # Keep only the 3 newest snapshots`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			managing := opts.List || opts.Delete != "" || opts.Prune
+			if managing && (opts.Name != "" || opts.Schema != "") {
+				log.Fatal("--name and --schema cannot be combined with --list, --delete, or --prune")
+			}
+			if cmd.Flags().Changed("keep") && !opts.Prune {
+				log.Fatal("--keep requires --prune")
+			}
+			if cmd.Flags().Changed("format") && !opts.List {
+				log.Fatal("--format requires --list")
+			}
+			switch {
+			case opts.List:
+				runDBSnapshotList(opts)
+			case opts.Delete != "":
+				runDBSnapshotDelete(opts)
+			case opts.Prune:
+				runDBSnapshotPrune(opts)
+			default:
+				runDBSnapshot(opts)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Snapshot name (default: <branch>-<timestamp>)")
+	cmd.Flags().StringVar(&opts.Schema, "schema", "", "Snapshot only this PostgreSQL schema")
+	cmd.Flags().BoolVar(&opts.List, "list", false, "List snapshots, newest first")
+	cmd.Flags().StringVar(&opts.Delete, "delete", "", "Delete the named snapshot")
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Delete all but the newest --keep snapshots")
+	cmd.Flags().IntVar(&opts.Keep, "keep", 5, "Number of snapshots to keep with --prune")
+	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Skip the confirmation prompt for --delete and --prune")
+	cmd.Flags().StringVar(&opts.Format, "format", string(output.FormatTable), output.FlagUsage)
+	cmd.MarkFlagsMutuallyExclusive("list", "delete", "prune")
+	_ = cmd.RegisterFlagCompletionFunc("delete", completeSnapshotFiles)
+
+	return cmd
+}
+
+func runDBSnapshot(opts *DBSnapshotOptions) {
+	name, err := snapshotFileName(opts.Name, time.Now())
+	if err != nil {
+		log.Fatalf("Invalid --name: %v", err)
+	}
+	if err := paths.EnsureSnapshotsDir(); err != nil {
+		log.Fatalf("Failed to create snapshots directory: %v", err)
+	}
+
+	outputPath := filepath.Join(paths.SnapshotsDir(), name)
+	runDBDump(&DBDumpOptions{
+		Format:        postgres.FormatPlain,
+		Schema:        opts.Schema,
+		Output:        outputPath,
+		CompressLevel: postgres.DefaultCompressLevel,
+		Gzip:          true,
+	})
+	fmt.Println(outputPath)
+}
+
+// snapshotFileName returns the file name for a snapshot: name with the
+// .sql.gz extension, or <branch>-<timestamp>.sql.gz when name is empty.
+func snapshotFileName(name string, now time.Time) (string, error) {
+	if name == "" {
+		return fmt.Sprintf("%s-%s%s", snapshotPrefix(), now.Format("20060102_150405"), gzipSnapshotExt), nil
+	}
+	name = strings.TrimSuffix(name, gzipSnapshotExt)
+	if name == "" || unsafeSnapshotChars.MatchString(name) || strings.Trim(name, ".") == "" {
+		return "", fmt.Errorf("%q must be non-empty and use only letters, digits, '.', '_', and '-'", name)
+	}
+	return name + gzipSnapshotExt, nil
+}
+
+func listSnapshots() []postgres.Snapshot {
+	snapshots, err := postgres.ListSnapshots(paths.SnapshotsDir())
+	if err != nil {
+		log.Fatalf("Failed to list snapshots: %v", err)
+	}
+	return snapshots
+}
+
+func runDBSnapshotList(opts *DBSnapshotOptions) {
+	format := parseOutputFormat(opts.Format)
+	snapshots := listSnapshots()
+	if len(snapshots) == 0 && format == output.FormatTable {
+		log.Infof("No snapshots in %s", paths.SnapshotsDir())
+		return
+	}
+
+	now := time.Now()
+	table := output.Table{Columns: []string{"NAME", "SIZE", "CREATED", "AGE", "REVISION"}}
+	for _, s := range snapshots {
+		revision := ""
+		if s.Meta != nil {
+			revision = s.Meta.Revisions[string(alembic.SchemaDefault)]
+		}
+		table.AddRow(s.Name, humanizeBytes(s.Size), s.CreatedAt.Local().Format("2006-01-02 15:04"),
+			humanizeAge(now.Sub(s.CreatedAt)), revision)
+	}
+	if err := output.Write(os.Stdout, format, table); err != nil {
+		log.Fatalf("Failed to write snapshot list: %v", err)
+	}
+}
+
+func runDBSnapshotDelete(opts *DBSnapshotOptions) {
+	snapshot, ok := postgres.FindSnapshot(listSnapshots(), opts.Delete)
+	if !ok {
+		log.Fatalf("Snapshot %q not found in %s", opts.Delete, paths.SnapshotsDir())
+	}
+	deleteSnapshots([]postgres.Snapshot{snapshot}, opts.Yes)
+}
+
+func runDBSnapshotPrune(opts *DBSnapshotOptions) {
+	if opts.Keep < 0 {
+		log.Fatalf("--keep must be at least 0, got %d", opts.Keep)
+	}
+	snapshots := listSnapshots()
+	if len(snapshots) <= opts.Keep {
+		log.Infof("%d snapshot(s), nothing to prune (keeping %d)", len(snapshots), opts.Keep)
+		return
+	}
+	deleteSnapshots(snapshots[opts.Keep:], opts.Yes)
+}
+
+// deleteSnapshots removes snapshots after confirming unless yes is set.
+func deleteSnapshots(snapshots []postgres.Snapshot, yes bool) {
+	if !yes {
+		for _, s := range snapshots {
+			fmt.Printf("  %s (%s)\n", s.Name, humanizeBytes(s.Size))
+		}
+		msg := fmt.Sprintf("Delete %d snapshot(s)? (yes/no): ", len(snapshots))
+		if !prompt.Confirm(msg) {
+			log.Info("Aborted.")
+			return
+		}
+	}
+
+	var freed int64
+	for _, s := range snapshots {
+		if err := postgres.DeleteSnapshot(s); err != nil {
+			log.Fatal(err)
+		}
+		freed += s.Size
+		log.Infof("Deleted %s", s.Name)
+	}
+	log.Infof("Freed %s", humanizeBytes(freed))
+}
+
+// humanizeAge formats a duration as a short age like "45m", "3h", or "12d".
+func humanizeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+)
+
+// DBWaitUpgradeOptions holds options for the db wait-and-upgrade command.
+type DBWaitUpgradeOptions struct {
+	Timeout    time.Duration
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// NewDBWaitUpgradeCommand creates the db wait-and-upgrade command.
+func NewDBWaitUpgradeCommand() *cobra.Command {
+	opts := &DBWaitUpgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "wait-and-upgrade",
+		Short: "Wait for PostgreSQL to be healthy, then upgrade both schemas to head",
+		Long: `Wait for the PostgreSQL container to be running and healthy, then run
+Alembic upgrades to head on the default and private schemas.
+
+Postgres can report healthy a moment before it accepts every connection, so
+each upgrade is retried a few times before giving up. Intended for scripts
+that start the stack and need migrations applied before continuing.
+
+Examples:
+  ods compose dev --wait=false && ods db wait-and-upgrade
+  ods db wait-and-upgrade --timeout 10m --retries 5`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runDBWaitUpgrade(opts)
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 5*time.Minute, "How long to wait for the PostgreSQL container to become healthy")
+	cmd.Flags().IntVar(&opts.Retries, "retries", 3, "Attempts per schema upgrade before failing")
+	cmd.Flags().DurationVar(&opts.RetryDelay, "retry-delay", 3*time.Second, "Delay between upgrade attempts")
+
+	return cmd
+}
+
+func runDBWaitUpgrade(opts *DBWaitUpgradeOptions) {
+	if opts.Retries < 1 {
+		log.Fatalf("--retries must be at least 1, got %d", opts.Retries)
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+
+	log.Info("Waiting for the PostgreSQL container...")
+	container, err := waitForPostgresContainer(deadline)
+	if err != nil {
+		log.Fatalf("PostgreSQL container did not start: %v", err)
+	}
+
+	log.Infof("Waiting for %s to be healthy...", container)
+	status, err := docker.WaitHealthy(container, time.Until(deadline))
+	if err != nil {
+		log.Fatalf("PostgreSQL container is not healthy: %v", err)
+	}
+	log.Infof("%s is %s", container, status)
+
+	upgradeAllSchemasToHead(opts.Retries, opts.RetryDelay, true)
+
+	log.Info("Database is up and migrated")
+}
+
+// waitForPostgresContainer polls until a PostgreSQL container is running or
+// the deadline passes.
+func waitForPostgresContainer(deadline time.Time) (string, error) {
+	for {
+		container, err := docker.FindPostgresContainer(docker.ProjectName())
+		if err == nil {
+			return container, nil
+		}
+		if !errors.Is(err, docker.ErrContainerNotFound) || time.Now().After(deadline) {
+			return "", err
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/cache"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/prompt"
+)
+
+// DownOptions holds options for the down command.
+type DownOptions struct {
+	Clean         bool
+	RemoveVolumes bool
+	Yes           bool
+}
+
+// NewDownCommand creates the down command.
+func NewDownCommand() *cobra.Command {
+	opts := &DownOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "down [profile]",
+		Short: "Stop Onyx and optionally reset local ods state",
+		Long: `Stop the Onyx containers started by 'ods up' (default profile: dev).
+
+With --clean, also delete ods-managed caches (downloaded CI artifacts and
+traces under ~/.local/share/onyx-dev/cache/). Database snapshots are kept.
+
+With --remove-volumes, also delete the project's docker volumes, wiping the
+local database, search index, and file store.
+
+Anything destructive asks for confirmation unless --yes is passed.
+
+Examples:
+  ods down                           # stop containers
+  ods down --clean                   # stop containers and clear caches
+  ods down --clean --remove-volumes  # full reset to zero`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: validProfiles,
+		Run: func(cmd *cobra.Command, args []string) {
+			profile := "dev"
+			if len(args) > 0 {
+				profile = args[0]
+			}
+			runDown(profile, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Clean, "clean", false, "Also delete ods-managed caches")
+	cmd.Flags().BoolVar(&opts.RemoveVolumes, "remove-volumes", false, "Also delete the project's volumes (database, index, file store)")
+	cmd.Flags().BoolVar(&opts.Yes, "yes", false, "Skip confirmation prompts")
+
+	return cmd
+}
+
+func runDown(profile string, opts *DownOptions) {
+	validateProfile(profile)
+
+	// Confirm everything destructive up front so declining leaves nothing half done
+	composeOpts := &ComposeOptions{
+		Down:          true,
+		RemoveVolumes: opts.RemoveVolumes,
+		Yes:           opts.Yes,
+	}
+	if opts.RemoveVolumes && !confirmRemoveVolumes(profile, composeOpts) {
+		log.Info("Aborted.")
+		return
+	}
+	cacheDir := paths.CacheDir()
+	if opts.Clean && !opts.Yes {
+		msg := fmt.Sprintf("This will delete all ods caches under %s. Continue? (yes/no): ", cacheDir)
+		if !prompt.Confirm(msg) {
+			log.Info("Aborted.")
+			return
+		}
+	}
+
+	composeOpts.volumesConfirmed = true
+	runCompose(profile, composeOpts)
+
+	if opts.Clean {
+		removeCaches(cacheDir)
+	}
+
+	log.Infof("Project %q is down", docker.ProjectName())
+}
+
+// removeCaches deletes the ods caches under cacheDir. With --docker-dry-run it
+// only logs what it would remove.
+func removeCaches(cacheDir string) {
+	if docker.DryRun() {
+		log.Infof("[DRY RUN] Would remove ods caches (%s)", cacheDir)
+		return
+	}
+	skipped, err := cache.Prune(cacheDir)
+	if err != nil {
+		log.Fatalf("Failed to remove ods caches: %v", err)
+	}
+	for _, name := range skipped {
+		log.Warnf("Kept %s: another ods command is still writing to it", filepath.Join(cacheDir, name))
+	}
+	log.Infof("Removed ods caches (%s)", cacheDir)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+)
+
+func TestRemoveCaches_dryRunKeepsCache(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cacheDir := paths.CacheDir()
+	entry := filepath.Join(cacheDir, "traces", "abc", "trace.zip")
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entry, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	docker.SetDryRun(true)
+	defer docker.SetDryRun(false)
+	removeCaches(cacheDir)
+
+	if _, err := os.Stat(entry); err != nil {
+		t.Errorf("expected --docker-dry-run to leave the cache in place: %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewKubeCommand creates the parent kube command for ad-hoc cluster operations.
+func NewKubeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kube",
+		Short: "Run ad-hoc commands against Onyx Kubernetes clusters",
+		Long: `Run ad-hoc commands against Onyx Kubernetes clusters.
+
+Clusters are selected with -c/--context, which maps to a KUBE_CTX_<NAME>
+environment variable holding "<cluster> <region> <namespace>" (the same
+contexts 'ods whois' uses).
+
+Commands:
+  exec      Run a command on a pod`,
+	}
+
+	cmd.AddCommand(newKubeExecCommand())
+
+	return cmd
+}
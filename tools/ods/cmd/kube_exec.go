@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// KubeExecOptions holds options for the kube exec command.
+type KubeExecOptions struct {
+	Context string
+	Stdin   bool
+	TTY     bool
+}
+
+func newKubeExecCommand() *cobra.Command {
+	opts := &KubeExecOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "exec <pod-substring> -- <command> [args...]",
+		Short: "Run a command on a pod",
+		Long: `Run a command on the first ready pod whose name contains <pod-substring>.
+
+Without -i/-t the command's output is printed once it finishes. Pass -it for
+interactive commands such as shells; the session is attached to the terminal.
+
+Examples:
+  ods kube exec api-server -- env
+  ods kube exec -c control_plane celery-worker -- ps aux
+  ods kube exec -it api-server -- bash`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 1 || len(args) < 2 {
+				return fmt.Errorf("expected <pod-substring> -- <command> [args...]")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			runKubeExec(args[0], args[1:], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Context, "context", "c", "data_plane", "cluster context name (maps to KUBE_CTX_<NAME> env var)")
+	cmd.Flags().BoolVarP(&opts.Stdin, "stdin", "i", false, "pass stdin to the command")
+	cmd.Flags().BoolVarP(&opts.TTY, "tty", "t", false, "allocate a TTY for the command")
+
+	return cmd
+}
+
+func runKubeExec(podSubstring string, command []string, opts *KubeExecOptions) {
+	c := clusterFromEnv(opts.Context)
+	connectCluster(c)
+
+	pod, err := c.FindPod(podSubstring)
+	if err != nil {
+		fatalKubeError(fmt.Sprintf("Failed to find %s pod", podSubstring), c, err)
+	}
+	log.Infof("Using pod %s on %s", pod, c.Name)
+
+	if opts.Stdin || opts.TTY {
+		if err := c.ExecInteractive(pod, opts.Stdin, opts.TTY, command...); err != nil {
+			// The command's own output was already streamed; just pass on its exit code
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			fatalKubeError("Command failed", c, err)
+		}
+		return
+	}
+
+	out, err := c.ExecOnPod(pod, command...)
+	fmt.Print(out)
+	if err != nil {
+		fatalKubeError("Command failed", c, err)
+	}
+}
@@ -1,14 +1,35 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/logs"
 )
 
 // LogsOptions holds options for the logs command.
 type LogsOptions struct {
-	Follow bool
-	Tail   string
+	Follow  bool
+	Tail    string
+	NoColor bool
+	Grep    string
+	Output  string
+
+	Context int
+	Before  int
+	After   int
+
+	LevelColors bool
+
+	LastTraceback bool
+	AllTracebacks bool
+	Input         string
 }
 
 // NewLogsCommand creates a new logs command for viewing docker container logs
@@ -37,24 +58,106 @@ Examples:
   ods logs --tail 100 api_server
 
   # View logs without following
-  ods logs --follow=false`,
+  ods logs --follow=false
+
+  # Write plain logs to a file
+  ods logs --follow=false --no-color > logs.txt
+
+  # Search all services for a pattern (regex), tagged and colored per service
+  ods logs --grep 'tenant_abc|Traceback'
+
+  # Same, over the last 1000 lines, sorted by timestamp across services
+  ods logs --grep 'ERROR' --tail 1000 --follow=false
+
+  # Show 5 lines of surrounding timeline around each match, like grep -C
+  ods logs --grep 'Traceback' --context 5 --follow=false
+
+  # Save recent logs from all services, sorted by timestamp, to attach to an issue
+  ods logs --tail 5000 --follow=false --output onyx-logs.txt
+
+  # Color each line by level (DEBUG gray, WARNING yellow, ERROR red)
+  ods logs --level-colors
+
+  # Show the most recent Python traceback from the api_server
+  ods logs --last-traceback api_server
+
+  # Extract every traceback from a saved log file (or - for stdin)
+  ods logs --all-tracebacks --input onyx-logs.txt
+
+With --grep, each line is tagged with its service, filtered by the regex
+(matched against the service name and message), and colored per service.
+Without --follow, lines from all services are sorted by timestamp.
+--context/--before/--after keep surrounding lines around each match (after
+sorting); overlapping windows are merged and separated by "--".
+
+--output writes the processed (tagged, filtered, and sorted) lines to a file
+instead of the terminal, always without color.
+
+--level-colors colors each message by its detected level and prints a legend
+first.
+
+--last-traceback and --all-tracebacks reassemble multi-line Python tracebacks
+and print them with their service and timestamp. They read the current logs
+(without following) or, with --input, a saved log file or stdin.
+
+Color is disabled automatically when NO_COLOR is set or stdout is not a terminal.`,
 		Args: cobra.ArbitraryArgs,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return runningServiceNames(), cobra.ShellCompDirectiveNoFileComp
 		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if opts.LastTraceback && opts.AllTracebacks {
+				log.Fatal("--last-traceback and --all-tracebacks cannot be used together")
+			}
+			if opts.LastTraceback || opts.AllTracebacks {
+				runLogsTracebacks(args, opts)
+				return
+			}
+			if opts.Input != "" {
+				log.Fatal("--input requires --last-traceback or --all-tracebacks")
+			}
+			if opts.Context < 0 || opts.Before < 0 || opts.After < 0 {
+				log.Fatal("--context, --before, and --after must not be negative")
+			}
+			if !cmd.Flags().Changed("before") {
+				opts.Before = opts.Context
+			}
+			if !cmd.Flags().Changed("after") {
+				opts.After = opts.Context
+			}
+			if (opts.Before > 0 || opts.After > 0) && opts.Grep == "" {
+				log.Fatal("--context, --before, and --after require --grep")
+			}
 			runComposeLogs(args, opts)
 		},
 	}
 
 	cmd.Flags().BoolVar(&opts.Follow, "follow", true, "Follow log output")
 	cmd.Flags().StringVar(&opts.Tail, "tail", "", "Number of lines to show from the end of the logs (e.g. 100)")
+	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "Disable colored output")
+	cmd.Flags().StringVar(&opts.Grep, "grep", "", "Only show lines matching this regular expression")
+	cmd.Flags().IntVarP(&opts.Context, "context", "C", 0, "With --grep, show this many lines before and after each match")
+	cmd.Flags().IntVarP(&opts.Before, "before", "B", 0, "With --grep, show this many lines before each match (overrides --context)")
+	cmd.Flags().IntVarP(&opts.After, "after", "A", 0, "With --grep, show this many lines after each match (overrides --context)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Write processed logs to this file instead of the terminal")
+	cmd.Flags().BoolVar(&opts.LevelColors, "level-colors", false, "Color each line by its log level and print a legend")
+	cmd.Flags().BoolVar(&opts.LastTraceback, "last-traceback", false, "Print only the most recent Python traceback")
+	cmd.Flags().BoolVar(&opts.AllTracebacks, "all-tracebacks", false, "Print every Python traceback")
+	cmd.Flags().StringVar(&opts.Input, "input", "", "Read logs from this file (- for stdin) instead of docker compose, for --last-traceback/--all-tracebacks")
 
 	return cmd
 }
 
 func runComposeLogs(services []string, opts *LogsOptions) {
-	args := baseArgs("")
+	if opts.Grep != "" || opts.Output != "" || opts.LevelColors {
+		runLogsPipeline(services, opts)
+		return
+	}
+
+	args, err := baseArgs("")
+	if err != nil {
+		log.Fatal(err)
+	}
 	args = append(args, "logs")
 	if opts.Follow {
 		args = append(args, "-f")
@@ -62,8 +165,174 @@ func runComposeLogs(services []string, opts *LogsOptions) {
 	if opts.Tail != "" {
 		args = append(args, "--tail", opts.Tail)
 	}
+	if !colorEnabled(opts.NoColor) {
+		args = append(args, "--no-color")
+	}
 	args = append(args, services...)
 
 	log.Info("Viewing container logs...")
 	execDockerCompose(args, nil)
 }
+
+// runLogsPipeline streams docker compose logs through the logs package so
+// they can be filtered, tagged, colored, and (when not following) sorted.
+func runLogsPipeline(services []string, opts *LogsOptions) {
+	var pattern *regexp.Regexp
+	if opts.Grep != "" {
+		var err error
+		pattern, err = regexp.Compile(opts.Grep)
+		if err != nil {
+			log.Fatalf("Invalid --grep pattern: %v", err)
+		}
+	}
+
+	out := os.Stdout
+	color := colorEnabled(opts.NoColor)
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+		color = false
+	}
+
+	args, err := baseArgs("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	args = append(args, "logs", "--timestamps", "--no-color")
+	if opts.Follow {
+		args = append(args, "-f")
+	}
+	if opts.Tail != "" {
+		args = append(args, "--tail", opts.Tail)
+	}
+	args = append(args, services...)
+
+	log.Debugf("Running: docker %v", args)
+	dockerCmd := exec.Command("docker", args...)
+	dockerCmd.Dir = composeDir()
+	dockerCmd.Stderr = os.Stderr
+	stdout, err := dockerCmd.StdoutPipe()
+	if err != nil {
+		log.Fatalf("Failed to read docker compose logs: %v", err)
+	}
+	if err := dockerCmd.Start(); err != nil {
+		log.Fatalf("Docker compose failed: %v", err)
+	}
+
+	processOpts := logs.Options{
+		Pattern:     pattern,
+		Color:       color,
+		LevelColors: opts.LevelColors,
+		Sort:        !opts.Follow,
+		Before:      opts.Before,
+		After:       opts.After,
+	}
+	if opts.Output != "" {
+		log.Infof("Writing logs to %s", opts.Output)
+	}
+	if err := logs.Process(stdout, out, processOpts); err != nil {
+		log.Fatalf("Failed to process logs: %v", err)
+	}
+	if err := dockerCmd.Wait(); err != nil {
+		log.Fatalf("Docker compose failed: %v", err)
+	}
+}
+
+// runLogsTracebacks extracts Python tracebacks from the compose logs or from
+// opts.Input and prints the last one or all of them.
+func runLogsTracebacks(services []string, opts *LogsOptions) {
+	if opts.Input != "" && len(services) > 0 {
+		log.Fatal("Service arguments cannot be combined with --input")
+	}
+
+	var r io.Reader
+	var dockerCmd *exec.Cmd
+	switch {
+	case opts.Input == "-":
+		r = os.Stdin
+	case opts.Input != "":
+		f, err := os.Open(opts.Input)
+		if err != nil {
+			log.Fatalf("Failed to open input: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	default:
+		args, err := baseArgs("")
+		if err != nil {
+			log.Fatal(err)
+		}
+		args = append(args, "logs", "--timestamps", "--no-color")
+		if opts.Tail != "" {
+			args = append(args, "--tail", opts.Tail)
+		}
+		args = append(args, services...)
+
+		log.Debugf("Running: docker %v", args)
+		dockerCmd = exec.Command("docker", args...)
+		dockerCmd.Dir = composeDir()
+		dockerCmd.Stderr = os.Stderr
+		stdout, err := dockerCmd.StdoutPipe()
+		if err != nil {
+			log.Fatalf("Failed to read docker compose logs: %v", err)
+		}
+		if err := dockerCmd.Start(); err != nil {
+			log.Fatalf("Docker compose failed: %v", err)
+		}
+		r = stdout
+	}
+
+	entries, err := logs.ReadEntries(r)
+	if err != nil {
+		log.Fatalf("Failed to read logs: %v", err)
+	}
+	if dockerCmd != nil {
+		if err := dockerCmd.Wait(); err != nil {
+			log.Fatalf("Docker compose failed: %v", err)
+		}
+	}
+
+	// Compose groups output by service; order it so "last" means most recent
+	logs.SortByTimestamp(entries)
+	tracebacks := logs.Tracebacks(entries)
+	if len(tracebacks) == 0 {
+		log.Info("No tracebacks found")
+		return
+	}
+	if opts.LastTraceback {
+		tracebacks = tracebacks[len(tracebacks)-1:]
+	}
+
+	color := colorEnabled(opts.NoColor)
+	for i, tb := range tracebacks {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(logs.FormatTraceback(tb, color))
+	}
+}
+
+// colorEnabled reports whether output should be colored: not when --no-color
+// is passed, NO_COLOR is set (https://no-color.org), or stdout is not a TTY.
+func colorEnabled(noColor bool) bool {
+	if noColor {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
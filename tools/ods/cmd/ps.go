@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/output"
+)
+
+// PsOptions holds options for the ps command.
+type PsOptions struct {
+	All    bool
+	Format string
+}
+
+// NewPsCommand creates the ps command.
+func NewPsCommand() *cobra.Command {
+	opts := &PsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List the Onyx containers of the current compose project",
+		Long: `List the containers of the current compose project with their service,
+state, status (including health), and published ports.
+
+With --format json, each container is an object with the keys service,
+container, state, status, and ports.
+
+Examples:
+  ods ps                 # running containers
+  ods ps --all           # include stopped containers
+  ods ps --format json   # machine-readable output`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runPs(opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.All, "all", "a", false, "Include stopped containers")
+	cmd.Flags().StringVar(&opts.Format, "format", string(output.FormatTable), output.FlagUsage)
+
+	return cmd
+}
+
+func runPs(opts *PsOptions) {
+	format := parseOutputFormat(opts.Format)
+	project := docker.ProjectName()
+
+	containers, err := docker.ProjectContainers(project, opts.All)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if len(containers) == 0 && format == output.FormatTable {
+		fmt.Printf("No containers for project %q.\n", project)
+		return
+	}
+
+	table := output.Table{Columns: []string{"SERVICE", "CONTAINER", "STATE", "STATUS", "PORTS"}}
+	for _, c := range containers {
+		table.AddRow(c.Service, c.Name, c.State, c.Status, c.Ports)
+	}
+	if err := output.Write(os.Stdout, format, table); err != nil {
+		log.Fatalf("Failed to write containers: %v", err)
+	}
+}
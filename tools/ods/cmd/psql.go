@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/postgres"
+)
+
+// PsqlOptions holds options for the psql command.
+type PsqlOptions struct {
+	Command string
+}
+
+// NewPsqlCommand creates the psql command.
+func NewPsqlCommand() *cobra.Command {
+	opts := &PsqlOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "psql [-- psql-args...]",
+		Short: "Open a psql shell in the PostgreSQL container",
+		Long: `Open an interactive psql shell in the running PostgreSQL container,
+connected with the POSTGRES_* settings from the environment.
+
+Use -c to run a single SQL command and exit. Arguments after -- are passed
+to psql as is. When stdin is not a terminal (e.g. SQL piped in), psql runs
+without a TTY so the input is read as a script.
+
+Examples:
+  ods psql                                     # Interactive shell
+  ods psql -c "SELECT count(*) FROM document"  # One-off query
+  ods psql -- -x -c "SELECT * FROM alembic_version"
+  ods psql < query.sql                         # Run a script`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runPsql(opts, args)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Command, "command", "c", "", "Run a single SQL command and exit")
+
+	return cmd
+}
+
+func runPsql(opts *PsqlOptions, extraArgs []string) {
+	container, err := docker.FindPostgresContainer(docker.ProjectName())
+	if err != nil {
+		log.Fatalf("Failed to find PostgreSQL container: %v", err)
+	}
+	log.Debugf("Found PostgreSQL container: %s", container)
+
+	config := postgres.NewConfigFromEnv()
+	args := append([]string{"psql"}, config.PsqlArgs()...)
+	if opts.Command != "" {
+		args = append(args, "-c", opts.Command)
+	}
+	args = append(args, extraArgs...)
+
+	run := docker.ExecWithEnv
+	if isTerminal(os.Stdin) {
+		run = docker.ExecTTY
+	}
+	if err := run(container, config.Env(), args...); err != nil {
+		log.Fatalf("psql failed: %v", err)
+	}
+}
@@ -7,7 +7,8 @@ import (
 
 // PullOptions holds options for the pull command.
 type PullOptions struct {
-	Tag string
+	Tag      string
+	Platform string
 }
 
 // NewPullCommand creates a new pull command for pulling docker images
@@ -24,23 +25,37 @@ Examples:
   ods pull
 
   # Pull images with a specific tag
-  ods pull --tag edge`,
+  ods pull --tag edge
+
+  # Pull amd64 images regardless of the host architecture
+  ods pull --platform linux/amd64
+
+Images are pulled for the host architecture by default so that Apple Silicon
+machines don't end up with emulated amd64 images.`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			runComposePull(opts)
+			runComposePull("", opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Set the IMAGE_TAG for docker compose (e.g. edge, v2.10.4)")
+	cmd.Flags().StringVar(&opts.Platform, "platform", defaultPlatform(), "Set DOCKER_DEFAULT_PLATFORM for pulled images (empty to let docker decide)")
 
 	return cmd
 }
 
-func runComposePull(opts *PullOptions) {
-	args := baseArgs("")
+func runComposePull(profile string, opts *PullOptions) {
+	args, err := baseArgs(profile)
+	if err != nil {
+		log.Fatal(err)
+	}
 	args = append(args, "pull")
 
-	log.Info("Pulling images...")
-	execDockerCompose(args, envForTag(opts.Tag))
+	if opts.Platform != "" {
+		log.Infof("Pulling images for %s...", opts.Platform)
+	} else {
+		log.Info("Pulling images...")
+	}
+	execDockerCompose(args, append(envForTag(opts.Tag), envForPlatform(opts.Platform)...))
 	log.Info("Images pulled successfully")
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/docker"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/kube"
 )
 
 var (
@@ -18,6 +19,7 @@ var (
 type RootOptions struct {
 	Debug   bool
 	Project string
+	DryRun  bool
 }
 
 // NewRootCommand creates the root command.
@@ -38,12 +40,15 @@ func NewRootCommand() *cobra.Command {
 				DisableTimestamp: true,
 			})
 			docker.SetProjectFlags(opts.Project)
+			docker.SetDryRun(opts.DryRun)
+			kube.SetDryRun(opts.DryRun)
 		},
 		Version: fmt.Sprintf("%s\ncommit %s", Version, Commit),
 	}
 
 	cmd.PersistentFlags().BoolVar(&opts.Debug, "debug", false, "run in debug mode")
 	cmd.PersistentFlags().StringVar(&opts.Project, "project", "", "Docker Compose project name (default: basename of git root)")
+	cmd.PersistentFlags().BoolVar(&opts.DryRun, "docker-dry-run", false, "Log docker/kubectl commands that would change state instead of running them")
 
 	// Add subcommands
 	cmd.AddCommand(NewAuditCommand())
@@ -51,12 +56,14 @@ func NewRootCommand() *cobra.Command {
 	cmd.AddCommand(NewCheckLazyImportsCommand())
 	cmd.AddCommand(NewCherryPickCommand())
 	cmd.AddCommand(NewDBCommand())
+	cmd.AddCommand(NewPsqlCommand())
 	cmd.AddCommand(NewDeployCommand())
 	cmd.AddCommand(NewOpenAPICommand())
 	cmd.AddCommand(NewComposeCommand())
 	cmd.AddCommand(NewEnvCommand())
 	cmd.AddCommand(NewLogsCommand())
 	cmd.AddCommand(NewPullCommand())
+	cmd.AddCommand(NewPsCommand())
 	cmd.AddCommand(NewRunCICommand())
 	cmd.AddCommand(NewScreenshotDiffCommand())
 	cmd.AddCommand(NewDesktopCommand())
@@ -64,9 +71,13 @@ func NewRootCommand() *cobra.Command {
 	cmd.AddCommand(NewWebCommand())
 	cmd.AddCommand(NewLatestStableTagCommand())
 	cmd.AddCommand(NewWhoisCommand())
+	cmd.AddCommand(NewKubeCommand())
 	cmd.AddCommand(NewTraceCommand())
 	cmd.AddCommand(NewInstallSkillCommand())
 	cmd.AddCommand(NewReleaseCommand())
+	cmd.AddCommand(NewUpCommand())
+	cmd.AddCommand(NewDownCommand())
+	cmd.AddCommand(NewVersionCommand())
 
 	return cmd
 }
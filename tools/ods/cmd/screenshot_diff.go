@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,7 +11,10 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/cache"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/git"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/imgdiff"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/s3"
 )
 
@@ -46,15 +51,34 @@ func sanitizeRev(rev string) string {
 
 // ScreenshotDiffCompareOptions holds options for the compare subcommand.
 type ScreenshotDiffCompareOptions struct {
-	Project      string
-	Rev          string // revision whose baseline to compare against (default: "main")
-	FromRev      string // cross-revision mode: source (older) revision
-	ToRev        string // cross-revision mode: target (newer) revision
-	Baseline     string
-	Current      string
-	Output       string
-	Threshold    float64
-	MaxDiffRatio float64
+	Project        string
+	Rev            string // revision whose baseline to compare against (default: "main")
+	FromRev        string // cross-revision mode: source (older) revision
+	ToRev          string // cross-revision mode: target (newer) revision
+	BaselineRunID  string // CI run whose screenshot artifacts are the baseline
+	BaselineMain   bool   // use the latest successful Playwright run on main as the baseline
+	CurrentRunID   string // CI run whose screenshot artifacts are the current side
+	NoCache        bool   // re-download run artifacts even when cached
+	Baseline       string
+	Current        string
+	Output         string
+	Threshold      float64
+	MaxDiffRatio   float64
+	MinDiffPercent float64 // pairs differing by less than this percentage count as unchanged
+	IgnoreNew      bool    // don't count added screenshots as failures
+	IgnoreRemoved  bool    // don't count removed screenshots as failures
+	OnlyChanged    bool    // skip downloading S3 baselines identical to the current screenshots
+	DiffOnly       bool    // omit unchanged pairs from the HTML report
+	MaskConfig     string
+	Masks          []string // inline "pattern:x,y,w,h" masks
+	JSON           bool     // write the per-screenshot results as JSON instead of an HTML report
+	Markdown       bool     // write a Markdown summary table instead of an HTML report
+	FailOnChange   bool     // exit non-zero when the summary has failures
+	Mode           string   // "exact" or "ssim"
+	SSIMThreshold  float64
+	Filter         string // only compare screenshots whose name matches this glob
+	Resize         bool   // scale the smaller image of a mismatched pair instead of padding it
+	NoEmbed        bool   // link images by file:// URL instead of inlining them
 }
 
 // ScreenshotDiffUploadOptions holds options for the upload-baselines subcommand.
@@ -115,6 +139,7 @@ You can override any default with explicit flags:
 
 func newCompareCommand() *cobra.Command {
 	opts := &ScreenshotDiffCompareOptions{}
+	embed := true
 
 	cmd := &cobra.Command{
 		Use:   "compare",
@@ -132,11 +157,23 @@ When --project is specified, the following defaults are applied:
   --output    → web/output/screenshot-diff/<project>/index.html
   --rev       → main
 
+Local directories passed explicitly via --baseline or --current must exist.
+
 The bucket defaults to "onyx-playwright-artifacts" and can be overridden
 with the PLAYWRIGHT_S3_BUCKET environment variable.
 
-A summary.json file is always written next to the HTML report. If there
-are no visual differences, the HTML report is skipped.
+A summary.json file is always written next to the HTML report, or to
+web/output/screenshot-diff/<project>/ when the report goes to stdout. If there
+are no visual differences, the HTML report is skipped. Otherwise the diff
+overlay of each changed screenshot (differing pixels in magenta over a dimmed
+copy of the current image) is also saved to diffs/<name>.diff.png, keeping the
+screenshot's extension in <name> (e.g. diffs/login.png.diff.png).
+
+The summary's "failures" count is what gating uses. --ignore-new and
+--ignore-removed exclude added/removed screenshots from it, so expected churn
+in the screenshot set doesn't fail the gate; those screenshots are still
+listed in the report. With --fail-on-change, the command exits with status 1
+when failures is non-zero, so it can gate a CI step directly.
 
 CROSS-REVISION MODE:
 
@@ -145,6 +182,24 @@ Both sides are downloaded from S3 — no local screenshots are needed.
 
   ods screenshot-diff compare --project admin --from-rev v1.0.0 --to-rev v2.0.0
 
+CI RUN MODE:
+
+Use --baseline-run-id and/or --current-run-id to take a side from the
+screenshots uploaded by a GitHub Actions run (the per-shard
+playwright-screenshots-<project>-* artifacts, merged). This requires
+--project and the gh CLI, and is handy for bisecting when a visual change
+landed. Artifacts expire after 30 days. Downloads are cached under
+~/.local/share/onyx-dev/cache/playwright-artifacts/<run-id>/ and reused;
+pass --no-cache to download them again.
+
+  ods screenshot-diff compare --project admin --baseline-run-id 12345 --current-run-id 12399
+
+--baseline-from-main looks up the latest successful Playwright run on main
+(main is tested through the merge queue) and uses its screenshots as the
+baseline, so comparing against main's latest CI doesn't need a run ID.
+
+  ods screenshot-diff compare --project admin --baseline-from-main
+
 Examples:
 
   # Compare local screenshots against main (default)
@@ -159,13 +214,118 @@ Examples:
   # Override specific flags
   ods screenshot-diff compare --project admin --current ./custom-dir/
 
+  # Compare two arbitrary local folders
+  ods screenshot-diff compare --baseline ./baseline --current ./candidate
+
+With --only-changed, S3 baselines whose checksum (ETag) matches the current
+screenshot are not downloaded; the current file stands in for them. This
+cuts download time for large suites where most screenshots are unchanged.
+If the object listing isn't available, all baselines are downloaded.
+
+  # Only download baselines that differ from the local screenshots
+  ods screenshot-diff compare --project admin --only-changed
+
+With --mask-config, regions of screenshots that always differ (clocks,
+avatars) are excluded from the comparison. The file is JSON mapping
+screenshot-name globs to rectangles in pixels:
+
+  {"admin-*.png": [{"x": 1100, "y": 8, "width": 160, "height": 32}]}
+
+Masked regions are shown in gray in the diff overlay and listed in the report.
+Without --mask-config, a masks.json in a local --current directory is used.
+Single regions can also be given inline with --mask pattern:x,y,w,h.
+Patterns are matched against the screenshot's file name (the name that pairs
+baseline and current files), not its full path.
+
+  ods screenshot-diff compare --project admin --mask-config masks.json
+  ods screenshot-diff compare --project admin --mask 'admin-*.png:1100,8,160,32'
+
+--threshold sets how far a channel may differ (as a fraction of 255) before
+the pixel counts as different. With --min-diff-percent, pairs where fewer
+than that percentage of pixels differ are reported as unchanged, which
+absorbs font anti-aliasing noise across machines.
+
+  ods screenshot-diff compare --project admin --min-diff-percent 0.1
+
+With --mode ssim, pairs are judged by their structural similarity index
+(SSIM, averaged over 8x8 windows) instead of the raw share of differing
+pixels, and are changed only when the score drops below --ssim-threshold
+(default 0.98). This tolerates sub-pixel rendering differences between
+platforms while still catching layout changes. --min-diff-percent does not
+apply in this mode.
+
+  ods screenshot-diff compare --project admin --mode ssim
+
+When a pair's dimensions differ, the smaller image is padded with
+transparent pixels, so all of the extra area counts as changed. --resize
+scales it to the larger image's size instead, which shows the actual visual
+change when, say, a page just grew taller. Either way, the report and
+summary note the old and new dimensions (e.g. 1280x720 → 1280x900).
+
+  ods screenshot-diff compare --project admin --resize
+
+--filter limits the comparison to screenshots whose file name matches a
+glob, which is much faster when iterating on a single page. Other
+screenshots are not decoded and are left out of the report and summary.
+
+  ods screenshot-diff compare --project admin --filter 'admin-connectors-*'
+
+The HTML report is a single self-contained file: every image is inlined as
+a base64 data URI, so it can be moved or attached to a PR. For large local
+suites, --embed=false links the images by file:// URL instead, which keeps
+the report small but ties it to this machine.
+
+With --json, the per-screenshot results (name, status, diff_percent,
+baseline/current/diff paths) are written as a JSON array instead of the HTML
+report, in the same order as the report. They go to stdout unless --output is
+given.
+
+  ods screenshot-diff compare --project admin --json | jq '.[] | select(.status == "changed")'
+
+With --markdown, a compact Markdown summary (a header with the counts and a
+table of status, name, and diff % for every pair that isn't unchanged) is
+written instead, ready to post as a PR comment. Like --json, it goes to
+stdout unless --output is given.
+
+  ods screenshot-diff compare --project admin --markdown | gh pr comment 123 --body-file -
+
   # Fully manual (no project flag)
   ods screenshot-diff compare \
     --baseline s3://my-bucket/baselines/admin/main/ \
     --current ./web/output/screenshots/ \
     --output ./web/output/screenshot-diff/admin/index.html`,
 		Run: func(cmd *cobra.Command, args []string) {
-			runCompare(opts)
+			if err := validateExplicitDirs(cmd, "baseline", "current"); err != nil {
+				log.Fatal(err)
+			}
+			if opts.BaselineRunID != "" && cmd.Flags().Changed("baseline") {
+				log.Fatal("--baseline-run-id and --baseline are mutually exclusive")
+			}
+			if opts.BaselineMain && (opts.BaselineRunID != "" || cmd.Flags().Changed("baseline")) {
+				log.Fatal("--baseline-from-main can't be combined with --baseline-run-id or --baseline")
+			}
+			if opts.CurrentRunID != "" && cmd.Flags().Changed("current") {
+				log.Fatal("--current-run-id and --current are mutually exclusive")
+			}
+			opts.NoEmbed = !embed
+			// --json and --markdown write to stdout unless --output names a file
+			if (opts.JSON || opts.Markdown) && !cmd.Flags().Changed("output") {
+				opts.Output = "-"
+			}
+			// Keep stdout clean for the HTML report when --output=-
+			var out io.Writer = os.Stdout
+			if opts.Output == "-" {
+				out = os.Stderr
+			}
+			result, err := runCompare(opts, out)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if opts.FailOnChange && result.Summary.Failures > 0 {
+				s := result.Summary
+				log.Fatalf("Visual differences found: %d changed, %d added, %d removed, %d error(s) (%d counted as failures)",
+					s.Changed, s.Added, s.Removed, s.Errors, s.Failures)
+			}
 		},
 	}
 
@@ -173,11 +333,31 @@ Examples:
 	cmd.Flags().StringVar(&opts.Rev, "rev", "", "Revision to compare against (default: main). Ignored when --from-rev/--to-rev are set")
 	cmd.Flags().StringVar(&opts.FromRev, "from-rev", "", "Source (older) revision for cross-revision comparison")
 	cmd.Flags().StringVar(&opts.ToRev, "to-rev", "", "Target (newer) revision for cross-revision comparison")
+	cmd.Flags().StringVar(&opts.BaselineRunID, "baseline-run-id", "", "GitHub Actions run ID whose screenshot artifacts are the baseline (requires --project)")
+	cmd.Flags().BoolVar(&opts.BaselineMain, "baseline-from-main", false, "Use the screenshots of the latest successful Playwright run on main as the baseline (requires --project)")
+	cmd.Flags().StringVar(&opts.CurrentRunID, "current-run-id", "", "GitHub Actions run ID whose screenshot artifacts are the current screenshots (requires --project)")
+	cmd.Flags().BoolVar(&opts.NoCache, "no-cache", false, "Re-download run artifacts instead of reusing the cached copy")
 	cmd.Flags().StringVar(&opts.Baseline, "baseline", "", "Baseline directory or S3 URL (s3://...)")
 	cmd.Flags().StringVar(&opts.Current, "current", "", "Current screenshots directory or S3 URL (s3://...)")
-	cmd.Flags().StringVar(&opts.Output, "output", "", "Output path for the HTML report")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "Output path for the HTML report, or - to write it to stdout")
 	cmd.Flags().Float64Var(&opts.Threshold, "threshold", 0.2, "Per-channel pixel difference threshold (0.0-1.0)")
 	cmd.Flags().Float64Var(&opts.MaxDiffRatio, "max-diff-ratio", 0.01, "Max diff pixel ratio before marking as changed (informational)")
+	cmd.Flags().StringVar(&opts.Mode, "mode", string(imgdiff.ModeExact), "Comparison mode: exact (count differing pixels) or ssim (structural similarity)")
+	cmd.Flags().Float64Var(&opts.SSIMThreshold, "ssim-threshold", imgdiff.DefaultSSIMThreshold, "With --mode ssim, mark pairs scoring below this (0-1) as changed")
+	cmd.Flags().Float64Var(&opts.MinDiffPercent, "min-diff-percent", 0, "Report pairs whose differing pixels are below this percentage (0-100) as unchanged")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Only compare and report screenshots whose file name matches this glob (e.g. 'admin-connectors-*')")
+	cmd.Flags().BoolVar(&opts.Resize, "resize", false, "Scale the smaller image of a pair with mismatched dimensions to the larger one before comparing, instead of padding it")
+	cmd.Flags().BoolVar(&embed, "embed", true, "Inline images in the HTML report; --embed=false links them by file:// URL instead (local directories only)")
+	cmd.Flags().BoolVar(&opts.FailOnChange, "fail-on-change", false, "Exit with status 1 when the summary has failures (changed, added, removed, or unreadable screenshots)")
+	cmd.Flags().BoolVar(&opts.IgnoreNew, "ignore-new", false, "Don't count added screenshots as failures (they are still listed in the report)")
+	cmd.Flags().BoolVar(&opts.IgnoreRemoved, "ignore-removed", false, "Don't count removed screenshots as failures (they are still listed in the report)")
+	cmd.Flags().BoolVar(&opts.OnlyChanged, "only-changed", false, "Skip downloading S3 baselines that are identical to the current screenshots")
+	cmd.Flags().BoolVar(&opts.DiffOnly, "diff-only", false, "Omit unchanged pairs from the HTML report for a smaller, faster-loading page")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Write the per-screenshot results as JSON (to stdout, or to --output) instead of an HTML report")
+	cmd.Flags().BoolVar(&opts.Markdown, "markdown", false, "Write a Markdown summary table for a PR comment (to stdout, or to --output) instead of an HTML report")
+	cmd.MarkFlagsMutuallyExclusive("json", "markdown")
+	cmd.Flags().StringVar(&opts.MaskConfig, "mask-config", "", "JSON file mapping screenshot-name globs to regions to ignore (default: masks.json in a local --current directory, if present)")
+	cmd.Flags().StringArrayVar(&opts.Masks, "mask", nil, "Region to ignore as pattern:x,y,w,h (e.g. 'admin-*.png:1100,8,160,32'). Can be specified multiple times")
 
 	return cmd
 }
@@ -233,6 +413,32 @@ Examples:
 	return cmd
 }
 
+// validateExplicitDirs checks that each named flag, when set by the user to a
+// local path, points at an existing directory. Defaults derived from --project
+// are not checked here since a missing default directory is handled gracefully.
+func validateExplicitDirs(cmd *cobra.Command, flagNames ...string) error {
+	for _, name := range flagNames {
+		if !cmd.Flags().Changed(name) {
+			continue
+		}
+		dir, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(dir, "s3://") {
+			continue
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("--%s directory %s: %w", name, dir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("--%s %s is not a directory", name, dir)
+		}
+	}
+	return nil
+}
+
 // resolveCompareDefaults fills in missing flags from the --project default when set.
 func resolveCompareDefaults(opts *ScreenshotDiffCompareOptions) {
 	bucket := getS3Bucket()
@@ -254,11 +460,11 @@ func resolveCompareDefaults(opts *ScreenshotDiffCompareOptions) {
 			if rev == "" {
 				rev = DefaultRev
 			}
-			if opts.Baseline == "" {
+			if opts.Baseline == "" && opts.BaselineRunID == "" {
 				opts.Baseline = fmt.Sprintf("s3://%s/baselines/%s/%s/",
 					bucket, opts.Project, sanitizeRev(rev))
 			}
-			if opts.Current == "" {
+			if opts.Current == "" && opts.CurrentRunID == "" {
 				opts.Current = DefaultScreenshotDir
 			}
 		}
@@ -309,21 +515,294 @@ func downloadS3Dir(s3URL string, prefix string) (string, error) {
 	return tmpDir, nil
 }
 
-func runCompare(opts *ScreenshotDiffCompareOptions) {
+// downloadChangedBaselines downloads an S3 baseline prefix into a temporary
+// directory, skipping objects whose ETag matches the corresponding file in
+// currentDir. Matching files are copied from currentDir instead, which also
+// makes "aws s3 sync" treat them as up to date. Falls back to a full download
+// when the prefix can't be listed.
+func downloadChangedBaselines(s3URL, currentDir string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "screenshot-baseline-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	objects, err := s3.ListObjects(s3URL)
+	if err != nil {
+		log.Warnf("Can't list baselines (%v); downloading all of them", err)
+	}
+
+	reused := 0
+	for _, obj := range objects {
+		local := filepath.Join(currentDir, filepath.FromSlash(obj.Key))
+		if match, err := obj.MatchesFile(local); err != nil || !match {
+			continue
+		}
+		if err := copyFile(local, filepath.Join(tmpDir, filepath.FromSlash(obj.Key))); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return "", err
+		}
+		reused++
+	}
+	if len(objects) > 0 {
+		log.Infof("%d of %d baselines are unchanged; downloading the other %d", reused, len(objects), len(objects)-reused)
+	}
+
+	if err := s3.SyncDown(s3URL, tmpDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("failed to download from S3 (%s): %w", s3URL, err)
+	}
+	return tmpDir, nil
+}
+
+// downloadRunScreenshots downloads the screenshot artifacts a CI run uploaded
+// for project and returns the directory holding them. Each shard is uploaded
+// as its own artifact, so they are merged into one directory, as the
+// visual-regression job does.
+//
+// Downloads are cached under paths.CacheDir() and reused by later runs, since
+// a run's artifacts never change. noCache discards the cached entry first.
+func downloadRunScreenshots(runID, project string, noCache bool) (string, error) {
+	destDir := filepath.Join(paths.CacheDir(), "playwright-artifacts", runID, project)
+
+	// An entry without any PNGs is an empty or unusable download; drop it so
+	// we try again.
+	if cache.Exists(destDir) && (noCache || !containsPNG(destDir)) {
+		if err := os.RemoveAll(destDir); err != nil {
+			return "", fmt.Errorf("failed to clear cached screenshots: %w", err)
+		}
+	}
+
+	cached, err := cache.Populate(destDir, traceDownloadTimeout, func(tmpDir string) error {
+		downloadDir := filepath.Join(tmpDir, ".download")
+		args := []string{"run", "download", runID,
+			"--pattern", fmt.Sprintf("playwright-screenshots-%s-*", project),
+			"--dir", downloadDir}
+		log.Infof("Downloading screenshots from run %s...", runID)
+		log.Debugf("Running: gh %s", strings.Join(args, " "))
+
+		// Each retry starts from an empty directory so partial extractions don't collide
+		ghOpts := git.GHOptions{
+			Timeout: traceDownloadTimeout,
+			Stderr:  os.Stderr,
+			BeforeRetry: func() {
+				_ = os.RemoveAll(downloadDir)
+			},
+		}
+		if _, err := git.RunGH(ghOpts, args...); err != nil {
+			return fmt.Errorf("%w\nMake sure the run ID is correct and the artifacts haven't expired (30 day retention)", err)
+		}
+		if err := mergeArtifactDirs(downloadDir, tmpDir); err != nil {
+			return err
+		}
+		return os.RemoveAll(downloadDir)
+	})
+	if err != nil {
+		return "", err
+	}
+	if cached {
+		log.Infof("Using cached screenshots from run %s at %s", runID, destDir)
+	}
+	return destDir, nil
+}
+
+// mainMergeQueuePrefix starts the head branch of merge queue runs targeting
+// main. The Playwright workflow runs on main through the merge queue rather
+// than on push, so main's runs are found by this prefix.
+const mainMergeQueuePrefix = "gh-readonly-queue/main/"
+
+// findLatestMainRun returns the ID of the latest successful run of workflow
+// on main.
+func findLatestMainRun(workflow string) (string, error) {
+	log.Infof("Looking up latest successful %q run on main", workflow)
+
+	output, err := git.GH("run", "list",
+		"--workflow", workflow,
+		"--event", "merge_group",
+		"--status", "success",
+		"--limit", "50",
+		"--json", "databaseId,status,conclusion,headBranch,url",
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var runs []ghRun
+	if err := json.Unmarshal(output, &runs); err != nil {
+		return "", fmt.Errorf("failed to parse run list: %w", err)
+	}
+
+	run, ok := latestMainRun(runs)
+	if !ok {
+		return "", fmt.Errorf("no successful %q runs found on main", workflow)
+	}
+	log.Infof("Found run: %s", run.URL)
+	return fmt.Sprintf("%d", run.DatabaseID), nil
+}
+
+// latestMainRun returns the first of runs (newest first, as gh lists them)
+// that targeted main and succeeded.
+func latestMainRun(runs []ghRun) (ghRun, bool) {
+	for _, run := range runs {
+		if strings.HasPrefix(run.HeadBranch, mainMergeQueuePrefix) && run.Conclusion == "success" {
+			return run, true
+		}
+	}
+	return ghRun{}, false
+}
+
+// containsPNG reports whether any PNG file exists under dir.
+func containsPNG(dir string) bool {
+	found := false
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".png") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// mergeArtifactDirs copies the contents of every artifact directory under
+// root (as laid out by "gh run download --pattern") into dest.
+func mergeArtifactDirs(root, dest string) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded artifacts: %w", err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no screenshot artifacts found in %s", root)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		artifactDir := filepath.Join(root, entry.Name())
+		err := filepath.WalkDir(artifactDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(artifactDir, path)
+			if err != nil {
+				return err
+			}
+			return copyFile(path, filepath.Join(dest, rel))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to merge artifact %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// describeCompareSide returns how a compare side is shown in the log.
+func describeCompareSide(location, runID string) string {
+	if runID != "" {
+		return "run " + runID
+	}
+	return location
+}
+
+// copyFile copies src to dst, creating dst's parent directories.
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+// ScreenshotDiffResult is the outcome of a compare run.
+type ScreenshotDiffResult struct {
+	Summary     imgdiff.Summary
+	Results     []imgdiff.Result
+	SummaryPath string
+	// ReportPath is the HTML report location; empty when no report was written
+	// (no differences, or the report went to stdout).
+	ReportPath string
+}
+
+// runCompare compares the baseline and current screenshots described by opts,
+// prints a terminal summary to out, and writes summary.json and (when there are
+// differences) the HTML report.
+func runCompare(opts *ScreenshotDiffCompareOptions, out io.Writer) (*ScreenshotDiffResult, error) {
 	// Validate cross-revision flags are used together
 	if (opts.FromRev != "") != (opts.ToRev != "") {
-		log.Fatal("--from-rev and --to-rev must be used together")
+		return nil, fmt.Errorf("--from-rev and --to-rev must be used together")
+	}
+
+	hasRunID := opts.BaselineRunID != "" || opts.CurrentRunID != "" || opts.BaselineMain
+	if hasRunID && opts.FromRev != "" {
+		return nil, fmt.Errorf("--baseline-run-id/--current-run-id/--baseline-from-main can't be combined with --from-rev/--to-rev")
+	}
+	// Screenshot artifacts are uploaded per project
+	if hasRunID && opts.Project == "" {
+		return nil, fmt.Errorf("--baseline-run-id/--current-run-id/--baseline-from-main require --project")
+	}
+	if opts.BaselineMain {
+		runID, err := findLatestMainRun(playwrightWorkflow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find main's latest Playwright run: %w", err)
+		}
+		opts.BaselineRunID = runID
 	}
 
 	resolveCompareDefaults(opts)
 
 	// Validate required fields
-	if opts.Baseline == "" {
-		log.Fatal("--baseline is required (or use --project to set defaults)")
+	if opts.Baseline == "" && opts.BaselineRunID == "" {
+		return nil, fmt.Errorf("--baseline is required (or use --project to set defaults)")
+	}
+	if opts.Current == "" && opts.CurrentRunID == "" {
+		return nil, fmt.Errorf("--current is required (or use --project to set defaults)")
+	}
+
+	if opts.MinDiffPercent < 0 || opts.MinDiffPercent > 100 {
+		return nil, fmt.Errorf("--min-diff-percent must be between 0 and 100, got %g", opts.MinDiffPercent)
+	}
+	// Downloaded S3 screenshots live in temp dirs that are removed on return,
+	// so a linked report would point at nothing
+	if opts.NoEmbed && (strings.HasPrefix(opts.Baseline, "s3://") || strings.HasPrefix(opts.Current, "s3://")) {
+		return nil, fmt.Errorf("--embed=false requires local --baseline and --current directories")
 	}
-	if opts.Current == "" {
-		log.Fatal("--current is required (or use --project to set defaults)")
+
+	mode, err := imgdiff.ParseMode(opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SSIMThreshold < 0 || opts.SSIMThreshold > 1 {
+		return nil, fmt.Errorf("--ssim-threshold must be between 0 and 1, got %g", opts.SSIMThreshold)
+	}
+	compareOpts := imgdiff.CompareOptions{
+		Threshold:      opts.Threshold,
+		MinDiffPercent: opts.MinDiffPercent,
+		Mode:           mode,
+		SSIMThreshold:  opts.SSIMThreshold,
+		Resize:         opts.Resize,
+		Filter:         opts.Filter,
+	}
+	// Load masks before downloading anything so a bad config fails fast
+	masks, err := loadCompareMasks(opts)
+	if err != nil {
+		return nil, err
 	}
+	compareOpts.Masks = masks
 
 	// Determine the project name for the summary (use flag or derive from path)
 	project := opts.Project
@@ -339,26 +818,45 @@ func runCompare(opts *ScreenshotDiffCompareOptions) {
 		}
 	}()
 
-	// Resolve baseline directory
-	baselineDir := opts.Baseline
-	if strings.HasPrefix(opts.Baseline, "s3://") {
-		dir, err := downloadS3Dir(opts.Baseline, "screenshot-baseline-*")
+	// Resolve current directory (may also be S3 in cross-revision mode)
+	currentDir := opts.Current
+	if opts.CurrentRunID != "" {
+		dir, err := downloadRunScreenshots(opts.CurrentRunID, opts.Project, opts.NoCache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download current screenshots: %w", err)
+		}
+		currentDir = dir
+	} else if strings.HasPrefix(opts.Current, "s3://") {
+		dir, err := downloadS3Dir(opts.Current, "screenshot-current-*")
 		if err != nil {
-			log.Fatalf("Failed to download baselines: %v", err)
+			return nil, fmt.Errorf("failed to download current screenshots: %w", err)
 		}
 		tempDirs = append(tempDirs, dir)
-		baselineDir = dir
+		currentDir = dir
 	}
 
-	// Resolve current directory (may also be S3 in cross-revision mode)
-	currentDir := opts.Current
-	if strings.HasPrefix(opts.Current, "s3://") {
-		dir, err := downloadS3Dir(opts.Current, "screenshot-current-*")
+	// Resolve baseline directory. The current side is resolved first so that
+	// --only-changed can skip baselines identical to it.
+	baselineDir := opts.Baseline
+	if opts.BaselineRunID != "" {
+		dir, err := downloadRunScreenshots(opts.BaselineRunID, opts.Project, opts.NoCache)
 		if err != nil {
-			log.Fatalf("Failed to download current screenshots: %v", err)
+			return nil, fmt.Errorf("failed to download baselines: %w", err)
+		}
+		baselineDir = dir
+	} else if strings.HasPrefix(opts.Baseline, "s3://") {
+		var dir string
+		var err error
+		if opts.OnlyChanged {
+			dir, err = downloadChangedBaselines(opts.Baseline, currentDir)
+		} else {
+			dir, err = downloadS3Dir(opts.Baseline, "screenshot-baseline-*")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to download baselines: %w", err)
 		}
 		tempDirs = append(tempDirs, dir)
-		currentDir = dir
+		baselineDir = dir
 	}
 
 	// Verify baseline directory exists
@@ -367,64 +865,177 @@ func runCompare(opts *ScreenshotDiffCompareOptions) {
 		log.Warn("This may be the first run -- no baselines to compare against.")
 		// Create an empty dir so CompareDirectories works (all files will be "added")
 		if err := os.MkdirAll(baselineDir, 0755); err != nil {
-			log.Fatalf("Failed to create baseline directory: %v", err)
+			return nil, fmt.Errorf("failed to create baseline directory: %w", err)
 		}
 	}
 
-	// Resolve the output path
+	// Resolve the output path. With --output=- the report goes to stdout, so
+	// summary.json lands in the project's default output directory.
+	toStdout := opts.Output == "-"
 	outputPath := opts.Output
+	if toStdout {
+		outputPath = filepath.Join(DefaultOutputDir, project, "index.html")
+	}
 	if !filepath.IsAbs(outputPath) {
 		cwd, err := os.Getwd()
 		if err != nil {
-			log.Fatalf("Failed to get working directory: %v", err)
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
 		}
 		outputPath = filepath.Join(cwd, outputPath)
 	}
-	summaryPath := filepath.Join(filepath.Dir(outputPath), "summary.json")
+	result := &ScreenshotDiffResult{
+		SummaryPath: filepath.Join(filepath.Dir(outputPath), "summary.json"),
+	}
 
 	// If the current screenshots directory doesn't exist, write an empty summary and exit
 	if _, err := os.Stat(currentDir); os.IsNotExist(err) {
 		log.Warnf("Current screenshots directory does not exist: %s", currentDir)
 		log.Warn("No screenshots captured for this project — writing empty summary.")
 
-		summary := imgdiff.Summary{Project: project}
-		if err := imgdiff.WriteSummary(summary, summaryPath); err != nil {
-			log.Fatalf("Failed to write summary: %v", err)
+		result.Summary = imgdiff.Summary{Project: project}
+		if err := imgdiff.WriteSummary(result.Summary, result.SummaryPath); err != nil {
+			return nil, fmt.Errorf("failed to write summary: %w", err)
 		}
-		log.Infof("Summary written to: %s", summaryPath)
-		return
+		log.Infof("Summary written to: %s", result.SummaryPath)
+		return result, nil
 	}
 
 	log.Infof("Comparing screenshots...")
-	log.Infof("  Baseline: %s", opts.Baseline)
-	log.Infof("  Current:  %s", opts.Current)
+	log.Infof("  Baseline: %s", describeCompareSide(opts.Baseline, opts.BaselineRunID))
+	log.Infof("  Current:  %s", describeCompareSide(opts.Current, opts.CurrentRunID))
 	log.Infof("  Threshold: %.2f", opts.Threshold)
+	if opts.MinDiffPercent > 0 {
+		log.Infof("  Min diff:  %.2f%%", opts.MinDiffPercent)
+	}
+	if mode == imgdiff.ModeSSIM {
+		log.Infof("  Mode:      ssim (threshold %.3f)", opts.SSIMThreshold)
+	}
 
-	results, err := imgdiff.CompareDirectories(baselineDir, currentDir, opts.Threshold)
+	if len(compareOpts.Masks) > 0 {
+		log.Infof("  Masks:    %d pattern(s)", len(compareOpts.Masks))
+	}
+
+	results, err := imgdiff.CompareDirectories(baselineDir, currentDir, compareOpts)
 	if err != nil {
-		log.Fatalf("Comparison failed: %v", err)
+		return nil, fmt.Errorf("comparison failed: %w", err)
 	}
+	result.Results = results
+
+	// Build the summary once; it drives both the terminal output and summary.json
+	result.Summary = imgdiff.BuildSummary(project, results)
+	result.Summary.Ignore(opts.IgnoreNew, opts.IgnoreRemoved)
 
 	// Print terminal summary
-	printSummary(results)
+	printSummary(out, result.Summary, results)
+
+	// Write JSON summary (always)
+	if err := imgdiff.WriteSummary(result.Summary, result.SummaryPath); err != nil {
+		return nil, fmt.Errorf("failed to write summary: %w", err)
+	}
+	log.Infof("Summary written to: %s", result.SummaryPath)
+
+	if opts.JSON {
+		if toStdout {
+			return result, imgdiff.WriteResults(os.Stdout, results)
+		}
+		if err := imgdiff.SaveDiffImages(results, filepath.Join(filepath.Dir(outputPath), "diffs")); err != nil {
+			return nil, err
+		}
+		if err := writeOutputFile(outputPath, func(w io.Writer) error {
+			return imgdiff.WriteResults(w, results)
+		}); err != nil {
+			return nil, err
+		}
+		log.Infof("Results written to: %s", outputPath)
+		return result, nil
+	}
 
-	// Build and write JSON summary (always)
-	summary := imgdiff.BuildSummary(project, results)
-	if err := imgdiff.WriteSummary(summary, summaryPath); err != nil {
-		log.Fatalf("Failed to write summary: %v", err)
+	if opts.Markdown {
+		if toStdout {
+			return result, imgdiff.WriteMarkdown(os.Stdout, result.Summary, results)
+		}
+		if err := writeOutputFile(outputPath, func(w io.Writer) error {
+			return imgdiff.WriteMarkdown(w, result.Summary, results)
+		}); err != nil {
+			return nil, err
+		}
+		log.Infof("Markdown summary written to: %s", outputPath)
+		return result, nil
 	}
-	log.Infof("Summary written to: %s", summaryPath)
 
 	// Generate HTML report only if there are differences
-	if summary.HasDifferences {
+	reportOpts := imgdiff.ReportOptions{DiffOnly: opts.DiffOnly, LinkFiles: opts.NoEmbed}
+	if result.Summary.HasDifferences && opts.DiffOnly && result.Summary.Unchanged > 0 {
+		log.Infof("Omitting %d unchanged pair(s) from the report (--diff-only)", result.Summary.Unchanged)
+	}
+	if result.Summary.HasDifferences && toStdout {
+		if err := imgdiff.RenderReport(os.Stdout, results, reportOpts); err != nil {
+			return nil, fmt.Errorf("failed to generate report: %w", err)
+		}
+	} else if result.Summary.HasDifferences {
+		diffDir := filepath.Join(filepath.Dir(outputPath), "diffs")
+		if err := imgdiff.SaveDiffImages(results, diffDir); err != nil {
+			return nil, err
+		}
+		if result.Summary.Changed > 0 {
+			log.Infof("Diff images written to: %s", diffDir)
+		}
 		log.Infof("Generating report: %s", outputPath)
-		if err := imgdiff.GenerateReport(results, outputPath); err != nil {
-			log.Fatalf("Failed to generate report: %v", err)
+		if err := imgdiff.GenerateReport(results, outputPath, reportOpts); err != nil {
+			return nil, fmt.Errorf("failed to generate report: %w", err)
 		}
+		result.ReportPath = outputPath
 		log.Infof("Report generated successfully: %s", outputPath)
 	} else {
 		log.Infof("No visual differences detected — skipping report generation.")
 	}
+
+	return result, nil
+}
+
+// loadCompareMasks combines the mask config (--mask-config, or masks.json in a
+// local current directory) with any inline --mask regions.
+func loadCompareMasks(opts *ScreenshotDiffCompareOptions) (imgdiff.Masks, error) {
+	configPath := opts.MaskConfig
+	if configPath == "" && opts.CurrentRunID == "" && !strings.HasPrefix(opts.Current, "s3://") {
+		candidate := filepath.Join(opts.Current, imgdiff.MaskFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			log.Infof("Using masks from %s", candidate)
+			configPath = candidate
+		}
+	}
+
+	masks := imgdiff.Masks{}
+	if configPath != "" {
+		loaded, err := imgdiff.LoadMasks(configPath)
+		if err != nil {
+			return nil, err
+		}
+		masks = loaded
+	}
+
+	for _, spec := range opts.Masks {
+		pattern, rect, err := imgdiff.ParseMask(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --mask: %w", err)
+		}
+		masks[pattern] = append(masks[pattern], rect)
+	}
+	return masks, nil
+}
+
+// writeOutputFile creates path (and its directory) and fills it with write,
+// for the --json and --markdown outputs.
+func writeOutputFile(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	return write(f)
 }
 
 func runUploadBaselines(opts *ScreenshotDiffUploadOptions) {
@@ -457,44 +1068,46 @@ func runUploadBaselines(opts *ScreenshotDiffUploadOptions) {
 	log.Info("Baselines uploaded successfully.")
 }
 
-func printSummary(results []imgdiff.Result) {
-	changed, added, removed, unchanged := 0, 0, 0, 0
-	for _, r := range results {
-		switch r.Status {
-		case imgdiff.StatusChanged:
-			changed++
-		case imgdiff.StatusAdded:
-			added++
-		case imgdiff.StatusRemoved:
-			removed++
-		case imgdiff.StatusUnchanged:
-			unchanged++
-		}
-	}
-
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════╗")
-	fmt.Println("║          Visual Regression Summary           ║")
-	fmt.Println("╠══════════════════════════════════════════════╣")
-	fmt.Printf("║  Changed:   %-32d ║\n", changed)
-	fmt.Printf("║  Added:     %-32d ║\n", added)
-	fmt.Printf("║  Removed:   %-32d ║\n", removed)
-	fmt.Printf("║  Unchanged: %-32d ║\n", unchanged)
-	fmt.Printf("║  Total:     %-32d ║\n", len(results))
-	fmt.Println("╚══════════════════════════════════════════════╝")
-	fmt.Println()
-
-	if changed > 0 || added > 0 || removed > 0 {
+func printSummary(w io.Writer, summary imgdiff.Summary, results []imgdiff.Result) {
+	changed, added, removed, unchanged := summary.Changed, summary.Added, summary.Removed, summary.Unchanged
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "╔══════════════════════════════════════════════╗")
+	_, _ = fmt.Fprintln(w, "║          Visual Regression Summary           ║")
+	_, _ = fmt.Fprintln(w, "╠══════════════════════════════════════════════╣")
+	_, _ = fmt.Fprintf(w, "║  Changed:   %-32d ║\n", changed)
+	_, _ = fmt.Fprintf(w, "║  Added:     %-32d ║\n", added)
+	_, _ = fmt.Fprintf(w, "║  Removed:   %-32d ║\n", removed)
+	_, _ = fmt.Fprintf(w, "║  Unchanged: %-32d ║\n", unchanged)
+	if summary.Errors > 0 {
+		_, _ = fmt.Fprintf(w, "║  Errors:    %-32d ║\n", summary.Errors)
+	}
+	_, _ = fmt.Fprintf(w, "║  Total:     %-32d ║\n", summary.Total)
+	_, _ = fmt.Fprintf(w, "║  Differ:    %-32s ║\n", fmt.Sprintf("%.1f%% of pairs", summary.ChangedPercent))
+	_, _ = fmt.Fprintf(w, "║  Avg diff:  %-32s ║\n", fmt.Sprintf("%.2f%% (changed pairs)", summary.AverageDiffPercent))
+	if summary.IgnoreAdded || summary.IgnoreRemoved {
+		_, _ = fmt.Fprintf(w, "║  Failures:  %-32d ║\n", summary.Failures)
+	}
+	_, _ = fmt.Fprintln(w, "╚══════════════════════════════════════════════╝")
+	_, _ = fmt.Fprintln(w)
+
+	if changed > 0 || added > 0 || removed > 0 || summary.Errors > 0 {
 		for _, r := range results {
 			switch r.Status {
+			case imgdiff.StatusError:
+				_, _ = fmt.Fprintf(w, "  ✗ ERROR    %s: %s\n", r.Name, r.Error)
 			case imgdiff.StatusChanged:
-				fmt.Printf("  ⚠ CHANGED  %s (%.2f%% diff)\n", r.Name, r.DiffPercent)
+				if r.DimensionsChanged() {
+					_, _ = fmt.Fprintf(w, "  ⚠ CHANGED  %s (%.2f%% diff, %s → %s)\n", r.Name, r.DiffPercent, r.BaselineSize, r.CurrentSize)
+				} else {
+					_, _ = fmt.Fprintf(w, "  ⚠ CHANGED  %s (%.2f%% diff)\n", r.Name, r.DiffPercent)
+				}
 			case imgdiff.StatusAdded:
-				fmt.Printf("  ✚ ADDED    %s\n", r.Name)
+				_, _ = fmt.Fprintf(w, "  ✚ ADDED    %s\n", r.Name)
 			case imgdiff.StatusRemoved:
-				fmt.Printf("  ✖ REMOVED  %s\n", r.Name)
+				_, _ = fmt.Fprintf(w, "  ✖ REMOVED  %s\n", r.Name)
 			}
 		}
-		fmt.Println()
+		_, _ = fmt.Fprintln(w)
 	}
 }
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSolidPNG(t *testing.T, path string, c color.Color) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+}
+
+func TestRunCompare_withDifferences(t *testing.T) {
+	dir := t.TempDir()
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, A: 255}
+
+	writeSolidPNG(t, filepath.Join(dir, "baseline", "page.png"), white)
+	writeSolidPNG(t, filepath.Join(dir, "current", "page.png"), red)
+	writeSolidPNG(t, filepath.Join(dir, "current", "new.png"), white)
+
+	opts := &ScreenshotDiffCompareOptions{
+		Baseline:  filepath.Join(dir, "baseline"),
+		Current:   filepath.Join(dir, "current"),
+		Output:    filepath.Join(dir, "out", "index.html"),
+		Threshold: 0.2,
+	}
+
+	result, err := runCompare(opts, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Summary.Changed != 1 || result.Summary.Added != 1 {
+		t.Errorf("expected 1 changed and 1 added, got %+v", result.Summary)
+	}
+	if result.ReportPath != opts.Output {
+		t.Errorf("expected report at %s, got %q", opts.Output, result.ReportPath)
+	}
+	for _, path := range []string{result.ReportPath, result.SummaryPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+func TestRunCompare_noDifferencesSkipsReport(t *testing.T) {
+	dir := t.TempDir()
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	writeSolidPNG(t, filepath.Join(dir, "baseline", "page.png"), white)
+	writeSolidPNG(t, filepath.Join(dir, "current", "page.png"), white)
+
+	opts := &ScreenshotDiffCompareOptions{
+		Baseline:  filepath.Join(dir, "baseline"),
+		Current:   filepath.Join(dir, "current"),
+		Output:    filepath.Join(dir, "out", "index.html"),
+		Threshold: 0.2,
+	}
+
+	result, err := runCompare(opts, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Summary.HasDifferences {
+		t.Errorf("expected no differences, got %+v", result.Summary)
+	}
+	if result.ReportPath != "" {
+		t.Errorf("expected no report, got %q", result.ReportPath)
+	}
+	if _, err := os.Stat(result.SummaryPath); err != nil {
+		t.Errorf("expected summary.json to exist: %v", err)
+	}
+}
+
+func TestRunCompare_stdoutKeepsSummaryInProjectDir(t *testing.T) {
+	dir := t.TempDir()
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, A: 255}
+
+	writeSolidPNG(t, filepath.Join(dir, "baseline", "page.png"), white)
+	writeSolidPNG(t, filepath.Join(dir, "current", "page.png"), red)
+
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = devNull.Close() }()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	opts := &ScreenshotDiffCompareOptions{
+		Baseline:  filepath.Join(dir, "baseline"),
+		Current:   filepath.Join(dir, "current"),
+		Output:    "-",
+		JSON:      true,
+		Threshold: 0.2,
+	}
+
+	result, err := runCompare(opts, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := filepath.Join(cwd, DefaultOutputDir, "default", "summary.json")
+	if result.SummaryPath != want {
+		t.Errorf("expected summary at %s, got %s", want, result.SummaryPath)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("expected summary.json to exist: %v", err)
+	}
+	for _, path := range []string{filepath.Join(cwd, "summary.json"), filepath.Join(cwd, "diffs"), filepath.Join(filepath.Dir(want), "diffs")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written, stat err = %v", path, err)
+		}
+	}
+}
+
+func TestRunCompare_requiresBothRevs(t *testing.T) {
+	opts := &ScreenshotDiffCompareOptions{Project: "admin", FromRev: "v1.0.0"}
+	if _, err := runCompare(opts, io.Discard); err == nil {
+		t.Error("expected error when --from-rev is set without --to-rev")
+	}
+}
+
+func TestRunCompare_runIDRequiresProject(t *testing.T) {
+	opts := &ScreenshotDiffCompareOptions{BaselineRunID: "123", Current: t.TempDir()}
+	if _, err := runCompare(opts, io.Discard); err == nil {
+		t.Error("expected error when --baseline-run-id is set without --project")
+	}
+}
+
+func TestMergeArtifactDirs(t *testing.T) {
+	root := t.TempDir()
+	for shard, name := range map[string]string{"1": "a.png", "2": "nested/b.png"} {
+		path := filepath.Join(root, "playwright-screenshots-admin-shard-"+shard+"-99", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := t.TempDir()
+	if err := mergeArtifactDirs(root, dest); err != nil {
+		t.Fatalf("mergeArtifactDirs: %v", err)
+	}
+	for _, name := range []string{"a.png", "nested/b.png"} {
+		data, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Errorf("expected %s in merged dir: %v", name, err)
+		} else if string(data) != name {
+			t.Errorf("%s: got %q", name, data)
+		}
+	}
+}
+
+func TestContainsPNG(t *testing.T) {
+	dir := t.TempDir()
+	if containsPNG(dir) {
+		t.Error("expected an empty dir to contain no PNGs")
+	}
+	path := filepath.Join(dir, "nested", "a.PNG")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !containsPNG(dir) {
+		t.Error("expected a nested PNG to be found")
+	}
+}
+
+func TestLatestMainRun(t *testing.T) {
+	runs := []ghRun{
+		{DatabaseID: 5, Conclusion: "success", HeadBranch: "gh-readonly-queue/release/v2.5/pr-1-abc"},
+		{DatabaseID: 4, Conclusion: "failure", HeadBranch: "gh-readonly-queue/main/pr-2-def"},
+		{DatabaseID: 3, Conclusion: "success", HeadBranch: "gh-readonly-queue/main/pr-3-123"},
+		{DatabaseID: 2, Conclusion: "success", HeadBranch: "gh-readonly-queue/main/pr-4-456"},
+	}
+	run, ok := latestMainRun(runs)
+	if !ok || run.DatabaseID != 3 {
+		t.Errorf("latestMainRun = %+v, %v; want run 3", run, ok)
+	}
+	if _, ok := latestMainRun(runs[:2]); ok {
+		t.Error("expected no main run when none succeeded")
+	}
+}
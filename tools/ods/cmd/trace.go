@@ -12,10 +12,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/cache"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/git"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/tui"
@@ -23,13 +25,18 @@ import (
 
 const playwrightWorkflow = "Run Playwright Tests"
 
+// traceDownloadTimeout bounds a single artifact download attempt, and how long
+// a concurrent ods process waits for another's download of the same run.
+const traceDownloadTimeout = 10 * time.Minute
+
 // TraceOptions holds options for the trace command
 type TraceOptions struct {
-	Branch  string
-	PR      string
-	Project string
-	List    bool
-	NoOpen  bool
+	Branch   string
+	PR       string
+	Project  string
+	Workflow string
+	List     bool
+	NoOpen   bool
 }
 
 // traceInfo describes a single trace.zip found in the downloaded artifacts.
@@ -54,7 +61,9 @@ The run can be specified as:
   - A full GitHub Actions run URL
   - Omitted, to find the latest Playwright run for the current branch
 
-You can also look up the latest run by branch name or PR number.
+You can also look up the latest run by branch name or PR number. Lookups use
+the "Run Playwright Tests" workflow by default; --workflow accepts either a
+workflow name or its file name (e.g. pr-playwright-tests.yml).
 
 Examples:
   ods trace                          # latest run for current branch
@@ -63,6 +72,7 @@ Examples:
   ods trace --pr 9500                # latest run for PR #9500
   ods trace --branch main            # latest run for main branch
   ods trace --project admin          # only download admin project traces
+  ods trace --workflow pr-playwright-tests.yml  # look up runs of another workflow
   ods trace --list                   # list available traces without opening`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -73,6 +83,7 @@ Examples:
 	cmd.Flags().StringVarP(&opts.Branch, "branch", "b", "", "Find latest run for this branch")
 	cmd.Flags().StringVar(&opts.PR, "pr", "", "Find latest run for this PR number")
 	cmd.Flags().StringVarP(&opts.Project, "project", "p", "", "Filter to a specific project (admin, exclusive, lite)")
+	cmd.Flags().StringVar(&opts.Workflow, "workflow", playwrightWorkflow, "Workflow name or file name used to find the latest run")
 	cmd.Flags().BoolVarP(&opts.List, "list", "l", false, "List available traces without opening")
 	cmd.Flags().BoolVar(&opts.NoOpen, "no-open", false, "Download traces but don't open them")
 
@@ -143,7 +154,7 @@ func resolveRunID(args []string, opts *TraceOptions) (string, error) {
 	}
 
 	if opts.PR != "" {
-		return findLatestRunForPR(opts.PR)
+		return findLatestRunForPR(opts.PR, opts.Workflow)
 	}
 
 	branch := opts.Branch
@@ -159,7 +170,7 @@ func resolveRunID(args []string, opts *TraceOptions) (string, error) {
 		log.Infof("Using current branch: %s", branch)
 	}
 
-	return findLatestRunForBranch(branch)
+	return findLatestRunForBranch(branch, opts.Workflow)
 }
 
 var runURLPattern = regexp.MustCompile(`/actions/runs/(\d+)`)
@@ -178,19 +189,18 @@ func parseRunIDFromArg(arg string) (string, error) {
 	return "", fmt.Errorf("could not parse run ID from %q; expected a numeric ID or GitHub Actions URL", arg)
 }
 
-// findLatestRunForBranch finds the most recent Playwright workflow run for a branch.
-func findLatestRunForBranch(branch string) (string, error) {
-	log.Infof("Looking up latest Playwright run for branch: %s", branch)
+// findLatestRunForBranch finds the most recent run of workflow for a branch.
+func findLatestRunForBranch(branch, workflow string) (string, error) {
+	log.Infof("Looking up latest %q run for branch: %s", workflow, branch)
 
-	cmd := exec.Command("gh", "run", "list",
-		"--workflow", playwrightWorkflow,
+	output, err := git.GH("run", "list",
+		"--workflow", workflow,
 		"--branch", branch,
 		"--limit", "1",
 		"--json", "databaseId,status,conclusion,headBranch,url",
 	)
-	output, err := cmd.Output()
 	if err != nil {
-		return "", ghError(err, "gh run list failed")
+		return "", err
 	}
 
 	var runs []ghRun
@@ -199,7 +209,7 @@ func findLatestRunForBranch(branch string) (string, error) {
 	}
 
 	if len(runs) == 0 {
-		return "", fmt.Errorf("no Playwright runs found for branch %q", branch)
+		return "", fmt.Errorf("no %q runs found for branch %q", workflow, branch)
 	}
 
 	run := runs[0]
@@ -207,17 +217,16 @@ func findLatestRunForBranch(branch string) (string, error) {
 	return fmt.Sprintf("%d", run.DatabaseID), nil
 }
 
-// findLatestRunForPR finds the most recent Playwright workflow run for a PR.
-func findLatestRunForPR(prNumber string) (string, error) {
+// findLatestRunForPR finds the most recent run of workflow for a PR.
+func findLatestRunForPR(prNumber, workflow string) (string, error) {
 	log.Infof("Looking up branch for PR #%s", prNumber)
 
-	cmd := exec.Command("gh", "pr", "view", prNumber,
+	output, err := git.GH("pr", "view", prNumber,
 		"--json", "headRefName",
 		"--jq", ".headRefName",
 	)
-	output, err := cmd.Output()
 	if err != nil {
-		return "", ghError(err, "gh pr view failed")
+		return "", err
 	}
 
 	branch := strings.TrimSpace(string(output))
@@ -226,7 +235,7 @@ func findLatestRunForPR(prNumber string) (string, error) {
 	}
 
 	log.Infof("PR #%s is on branch: %s", prNumber, branch)
-	return findLatestRunForBranch(branch)
+	return findLatestRunForBranch(branch, workflow)
 }
 
 // downloadTraceArtifacts downloads playwright trace artifacts for a run.
@@ -236,39 +245,49 @@ func downloadTraceArtifacts(runID string, project string) (string, error) {
 	if project != "" {
 		cacheKey = runID + "-" + project
 	}
-	destDir := filepath.Join(os.TempDir(), "ods-traces", cacheKey)
+	destDir := filepath.Join(paths.CacheDir(), "traces", cacheKey)
 
-	// Reuse a previous download if traces exist
-	if info, err := os.Stat(destDir); err == nil && info.IsDir() {
-		traces, _ := findTraces(destDir)
-		if len(traces) > 0 {
-			log.Infof("Using cached download at %s", destDir)
-			return destDir, nil
+	// Entries are only renamed into place once fully downloaded, so an
+	// existing directory without traces is a real (empty) download, not a
+	// partial one. Drop it so we try again.
+	if cache.Exists(destDir) {
+		if traces, _ := findTraces(destDir); len(traces) == 0 {
+			_ = os.RemoveAll(destDir)
 		}
-		_ = os.RemoveAll(destDir)
-	}
-
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory %s: %w", destDir, err)
 	}
 
-	ghArgs := []string{"run", "download", runID, "--dir", destDir}
-
+	ghArgs := []string{"run", "download", runID}
 	if project != "" {
 		ghArgs = append(ghArgs, "--pattern", fmt.Sprintf("playwright-test-results-%s-*", project))
 	} else {
 		ghArgs = append(ghArgs, "--pattern", "playwright-test-results-*")
 	}
 
-	log.Infof("Downloading trace artifacts...")
-	log.Debugf("Running: gh %s", strings.Join(ghArgs, " "))
-
-	cmd := exec.Command("gh", ghArgs...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		_ = os.RemoveAll(destDir)
-		return "", fmt.Errorf("gh run download failed: %w\nMake sure the run ID is correct and the artifacts haven't expired (30 day retention)", err)
+	cached, err := cache.Populate(destDir, traceDownloadTimeout, func(tmpDir string) error {
+		args := append(ghArgs, "--dir", tmpDir)
+		log.Infof("Downloading trace artifacts...")
+		log.Debugf("Running: gh %s", strings.Join(args, " "))
+
+		// Each retry starts from an empty directory so partial extractions don't collide
+		ghOpts := git.GHOptions{
+			Timeout: traceDownloadTimeout,
+			Stdout:  os.Stdout,
+			Stderr:  os.Stderr,
+			BeforeRetry: func() {
+				_ = os.RemoveAll(tmpDir)
+				_ = os.MkdirAll(tmpDir, 0755)
+			},
+		}
+		if _, err := git.RunGH(ghOpts, args...); err != nil {
+			return fmt.Errorf("%w\nMake sure the run ID is correct and the artifacts haven't expired (30 day retention)", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if cached {
+		log.Infof("Using cached download at %s", destDir)
 	}
 
 	return destDir, nil
@@ -546,11 +565,3 @@ func openTraces(traces []traceInfo) {
 		log.Errorf("playwright show-trace failed: %v\nMake sure Playwright is installed (bunx playwright install)", err)
 	}
 }
-
-// ghError wraps a gh CLI error with stderr output.
-func ghError(err error, msg string) error {
-	if exitErr, ok := err.(*exec.ExitError); ok {
-		return fmt.Errorf("%s: %w: %s", msg, err, string(exitErr.Stderr))
-	}
-	return fmt.Errorf("%s: %w", msg, err)
-}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// defaultWebPort is the host port nginx is published on when HOST_PORT is unset.
+const defaultWebPort = "3000"
+
+// UpOptions holds options for the up command.
+type UpOptions struct {
+	NoStart   bool
+	NoWait    bool
+	NoMigrate bool
+	Tag       string
+	NoEE      bool
+}
+
+// NewUpCommand creates the up command.
+func NewUpCommand() *cobra.Command {
+	opts := &UpOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "up [profile]",
+		Short: "Start Onyx, wait for it to be healthy, and run migrations",
+		Long: `Bring up a working local Onyx in one command.
+
+This runs, in order:
+  1. docker compose up -d for the profile (default: dev)
+  2. waits for all services to report healthy
+  3. alembic upgrade head on the default and private schemas
+  4. prints the web URL
+
+Each step can be skipped. See 'ods compose' for the available profiles.
+
+Examples:
+  ods up                     # dev profile, all steps
+  ods up multitenant         # multitenant profile
+  ods up --no-migrate        # start and wait, skip migrations
+  ods up --no-start          # only run migrations against running containers
+  ods up --tag edge          # use a specific image tag`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: validProfiles,
+		Run: func(cmd *cobra.Command, args []string) {
+			profile := "dev"
+			if len(args) > 0 {
+				profile = args[0]
+			}
+			runUp(profile, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.NoStart, "no-start", false, "Skip starting containers")
+	cmd.Flags().BoolVar(&opts.NoWait, "no-wait", false, "Don't wait for services to be healthy after starting them")
+	cmd.Flags().BoolVar(&opts.NoMigrate, "no-migrate", false, "Skip running database migrations")
+	cmd.Flags().StringVar(&opts.Tag, "tag", "", "Set the IMAGE_TAG for docker compose (e.g. edge, v2.10.4)")
+	cmd.Flags().BoolVar(&opts.NoEE, "no-ee", false, "Disable Enterprise Edition features (enabled by default)")
+
+	return cmd
+}
+
+func runUp(profile string, opts *UpOptions) {
+	validateProfile(profile)
+
+	if opts.NoStart {
+		log.Info("Skipping container startup (--no-start)")
+	} else {
+		// compose up --wait blocks until every service with a healthcheck is healthy
+		runCompose(profile, &ComposeOptions{
+			Wait:     !opts.NoWait,
+			Tag:      opts.Tag,
+			Platform: defaultPlatform(),
+			NoEE:     opts.NoEE,
+		})
+	}
+
+	if opts.NoMigrate {
+		log.Info("Skipping migrations (--no-migrate)")
+	} else {
+		upgradeAllSchemasToHead(1, 0, false)
+	}
+
+	log.Infof("Onyx is up at %s", webURL())
+}
+
+// webURL returns the local URL nginx is published on, honoring HOST_PORT from
+// the environment or the compose .env file.
+func webURL() string {
+	port := os.Getenv("HOST_PORT")
+	if port == "" {
+		port = composeEnvValue("HOST_PORT")
+	}
+	if port == "" {
+		port = defaultWebPort
+	}
+	return fmt.Sprintf("http://localhost:%s", port)
+}
+
+// composeEnvValue returns the value of key in the compose .env file, or "" if
+// the file or key is missing.
+func composeEnvValue(key string) string {
+	f, err := os.Open(filepath.Join(composeDir(), ".env"))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	prefix := key + "="
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), prefix); ok {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return ""
+}
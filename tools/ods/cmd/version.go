@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/output"
+)
+
+// toolVersionTimeout bounds each external "--version" probe so a hung tool
+// (e.g. a docker daemon that isn't responding) can't stall the command.
+const toolVersionTimeout = 5 * time.Second
+
+// versionTools lists the external tools ods shells out to and how to ask each
+// one for its version.
+var versionTools = []struct {
+	Name string
+	Args []string
+}{
+	{"docker", []string{"--version"}},
+	{"gh", []string{"--version"}},
+	{"kubectl", []string{"version", "--client"}},
+	{"alembic", []string{"--version"}},
+}
+
+// VersionOptions holds options for the version command.
+type VersionOptions struct {
+	JSON   bool
+	Format string
+}
+
+// VersionInfo is the build and environment information printed by ods version.
+// It is the --format json schema; tools maps each name in versionTools to its
+// version line, or "not found".
+type VersionInfo struct {
+	Version   string            `json:"version"`
+	Commit    string            `json:"commit"`
+	GoVersion string            `json:"go_version"`
+	Platform  string            `json:"platform"`
+	Tools     map[string]string `json:"tools"`
+}
+
+// NewVersionCommand creates the version command.
+func NewVersionCommand() *cobra.Command {
+	opts := &VersionOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print ods build information and tool versions",
+		Long: `Print the ods version and commit, the Go runtime it was built with, and the
+versions of the external tools ods depends on (docker, gh, kubectl, alembic).
+
+Tools that are not installed are reported as "not found". Include this output
+when filing bug reports.
+
+With --format json the output is a single object with the keys version,
+commit, go_version, platform, and tools (tool name to version). --format csv
+writes component,version rows.
+
+Examples:
+  ods version                 # Human-readable output
+  ods version --format json   # Machine-readable output
+  ods version --json          # Same as --format json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.JSON {
+				opts.Format = string(output.FormatJSON)
+			}
+			runVersion(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Output as JSON (same as --format json)")
+	cmd.Flags().StringVar(&opts.Format, "format", string(output.FormatTable), output.FlagUsage)
+	cmd.MarkFlagsMutuallyExclusive("json", "format")
+
+	return cmd
+}
+
+func runVersion(opts *VersionOptions) {
+	format := parseOutputFormat(opts.Format)
+	info := collectVersionInfo()
+
+	switch format {
+	case output.FormatJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			log.Fatalf("Failed to encode version info: %v", err)
+		}
+		return
+	case output.FormatCSV:
+		table := output.Table{Columns: []string{"COMPONENT", "VERSION"}}
+		table.AddRow("ods", info.Version)
+		table.AddRow("commit", info.Commit)
+		table.AddRow("go", info.GoVersion)
+		table.AddRow("platform", info.Platform)
+		for _, tool := range versionTools {
+			table.AddRow(tool.Name, info.Tools[tool.Name])
+		}
+		if err := output.Write(os.Stdout, format, table); err != nil {
+			log.Fatalf("Failed to write version info: %v", err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "ods\t%s\n", info.Version)
+	_, _ = fmt.Fprintf(w, "commit\t%s\n", info.Commit)
+	_, _ = fmt.Fprintf(w, "go\t%s\n", info.GoVersion)
+	_, _ = fmt.Fprintf(w, "platform\t%s\n", info.Platform)
+	for _, tool := range versionTools {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", tool.Name, info.Tools[tool.Name])
+	}
+	_ = w.Flush()
+}
+
+func collectVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Tools:     make(map[string]string, len(versionTools)),
+	}
+	for _, tool := range versionTools {
+		info.Tools[tool.Name] = toolVersion(tool.Name, tool.Args...)
+	}
+	return info
+}
+
+// toolVersion runs a tool's version command and returns the first line of its
+// output, or a short description of why it couldn't be determined.
+func toolVersion(name string, args ...string) string {
+	if _, err := exec.LookPath(name); err != nil {
+		return "not found"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), toolVersionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "unknown (timed out)"
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if err != nil && line == "" {
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	return strings.TrimSpace(line)
+}
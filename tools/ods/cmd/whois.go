@@ -1,23 +1,50 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/onyx-dot-app/onyx/tools/ods/internal/kube"
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/output"
 )
 
 var safeIdentifier = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
 
+// whoisPodSubstring selects the pod that whois queries are executed on.
+const whoisPodSubstring = "api-server"
+
+// allAdminsBatchSize caps how many tenant schemas are combined into one
+// UNION ALL query so the pginto command line stays well under ARG_MAX.
+const allAdminsBatchSize = 200
+
+// tenantSchemasQuery lists every tenant schema in the data plane database.
+const tenantSchemasQuery = `SELECT schema_name FROM information_schema.schemata WHERE schema_name LIKE 'tenant\_%' ORDER BY schema_name;`
+
+// WhoisOptions holds options for the whois command
+type WhoisOptions struct {
+	Context   string
+	Explain   bool
+	AllAdmins bool
+	Output    string
+	Format    string
+
+	ActiveOnly      bool
+	IncludeInactive bool
+	WithAdmins      bool
+}
+
 // NewWhoisCommand creates the whois command for looking up users/tenants.
 func NewWhoisCommand() *cobra.Command {
-	var ctx string
+	opts := &WhoisOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "whois <email-fragment or tenant-id>",
@@ -31,10 +58,17 @@ Two modes (auto-detected):
   Email fragment:
     ods whois chris
     → Searches user_tenant_mapping for emails matching '%chris%'
+      (add --active-only to hide inactive mappings, or --with-admins to
+       also list the admins of every matching tenant, grouped by tenant)
 
   Tenant ID:
     ods whois tenant_abcd1234-...
-    → Lists all admin emails in that tenant
+    → Lists active admin emails in that tenant
+      (add --include-inactive to list deactivated admins too)
+
+  All tenants (audit):
+    ods whois --all-admins -o admins.csv
+    → Writes tenant_id,email for every active admin in every tenant schema
 
 Cluster connection is configured via KUBE_CTX_* environment variables.
 Each variable is a space-separated tuple: "cluster region namespace"
@@ -43,14 +77,41 @@ Each variable is a space-separated tuple: "cluster region namespace"
   export KUBE_CTX_CONTROL_PLANE="<cluster> <region> <namespace>"
   etc...
 
-Use -c to select which context (default: data_plane).`,
-		Args: cobra.ExactArgs(1),
+Use -c to select which context (default: data_plane).
+
+Use --explain to print the SQL and the target context/pod without connecting
+to the cluster or executing anything.
+
+Use --format to choose table (default), json, or csv output. --all-admins
+defaults to csv.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.AllAdmins {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			runWhois(args[0], ctx)
+			if opts.AllAdmins && !cmd.Flags().Changed("format") {
+				opts.Format = string(output.FormatCSV)
+			}
+			if opts.AllAdmins {
+				runWhoisAllAdmins(opts)
+				return
+			}
+			runWhois(args[0], opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&ctx, "context", "c", "data_plane", "cluster context name (maps to KUBE_CTX_<NAME> env var)")
+	cmd.Flags().StringVarP(&opts.Context, "context", "c", "data_plane", "cluster context name (maps to KUBE_CTX_<NAME> env var)")
+	cmd.Flags().BoolVar(&opts.Explain, "explain", false, "print the query and target pod/context without executing it")
+	cmd.Flags().BoolVar(&opts.AllAdmins, "all-admins", false, "dump active admins of every tenant as CSV (tenant_id,email)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "write --all-admins output to this file instead of stdout")
+	cmd.Flags().StringVar(&opts.Format, "format", string(output.FormatTable), output.FlagUsage)
+	cmd.Flags().BoolVar(&opts.ActiveOnly, "active-only", false, "only show active user_tenant_mapping rows when searching by email")
+	cmd.Flags().BoolVar(&opts.IncludeInactive, "include-inactive", false, "include deactivated admins when listing tenant admins")
+	cmd.Flags().BoolVar(&opts.WithAdmins, "with-admins", false, "when searching by email, also list the admins of each matching tenant")
+	cmd.MarkFlagsMutuallyExclusive("active-only", "include-inactive")
+	cmd.MarkFlagsMutuallyExclusive("all-admins", "with-admins")
 
 	return cmd
 }
@@ -74,7 +135,7 @@ func clusterFromEnv(name string) *kube.Cluster {
 func queryPod(c *kube.Cluster, pod, sql string) []string {
 	raw, err := c.ExecOnPod(pod, "pginto", "-A", "-t", "-F", "\t", "-c", sql)
 	if err != nil {
-		log.Fatalf("Query failed: %v", err)
+		fatalKubeError("Query failed", c, err)
 	}
 
 	var lines []string
@@ -87,73 +148,355 @@ func queryPod(c *kube.Cluster, pod, sql string) []string {
 	return lines
 }
 
-func runWhois(query string, ctx string) {
-	c := clusterFromEnv(ctx)
+func runWhois(query string, opts *WhoisOptions) {
+	format := parseOutputFormat(opts.Format)
+	c := clusterFromEnv(opts.Context)
+
+	var sql string
+	if strings.HasPrefix(query, "tenant_") {
+		if opts.WithAdmins {
+			log.Fatal("--with-admins only applies to email searches; a tenant ID lookup already lists its admins")
+		}
+		var err error
+		sql, err = buildTenantAdminsQuery(query, opts.IncludeInactive)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	} else {
+		sql = buildEmailQuery(query, opts.ActiveOnly)
+	}
+
+	if opts.Explain {
+		explainWhois(c, opts.Context, sql)
+		if opts.WithAdmins {
+			fmt.Println()
+			fmt.Println("-- then, for the tenants of the matching users:")
+			adminsSQL, _ := buildAllAdminsQuery([]string{"tenant_a", "tenant_b"}, opts.IncludeInactive)
+			fmt.Println(adminsSQL)
+		}
+		return
+	}
 
+	pod := connectWhois(c)
+
+	switch {
+	case strings.HasPrefix(query, "tenant_"):
+		findAdminsByTenant(c, pod, query, sql, format)
+	case opts.WithAdmins:
+		findByEmailWithAdmins(c, pod, query, sql, opts.IncludeInactive, format)
+	default:
+		findByEmail(c, pod, query, sql, format)
+	}
+}
+
+// connectCluster checks the AWS session and ensures the kube context exists.
+// It runs before any kube-backed work so an expired SSO login fails fast.
+func connectCluster(c *kube.Cluster) {
+	if err := kube.CheckAWSCredentials(); err != nil {
+		if errors.Is(err, kube.ErrCredentialsExpired) {
+			log.Fatalf("AWS credentials are expired or missing. Run 'aws sso login' and try again.\n\n%v", err)
+		}
+		log.Fatalf("Failed to check AWS credentials: %v", err)
+	}
 	if err := c.EnsureContext(); err != nil {
 		log.Fatalf("Failed to ensure cluster context: %v", err)
 	}
+}
+
+// connectWhois ensures the kube context and returns the pod queries run on.
+func connectWhois(c *kube.Cluster) string {
+	connectCluster(c)
 
-	log.Info("Finding api-server pod...")
-	pod, err := c.FindPod("api-server")
+	log.Infof("Finding %s pod...", whoisPodSubstring)
+	pod, err := c.FindPod(whoisPodSubstring)
 	if err != nil {
-		log.Fatalf("Failed to find api-server pod: %v", err)
+		fatalKubeError(fmt.Sprintf("Failed to find %s pod", whoisPodSubstring), c, err)
 	}
 	log.Debugf("Using pod: %s", pod)
+	return pod
+}
 
-	if strings.HasPrefix(query, "tenant_") {
-		findAdminsByTenant(c, pod, query)
-	} else {
-		findByEmail(c, pod, query)
+// fatalKubeError logs err with a remediation hint for the failures we can
+// recognize, then exits.
+func fatalKubeError(msg string, c *kube.Cluster, err error) {
+	switch {
+	case errors.Is(err, kube.ErrCredentialsExpired):
+		log.Fatalf("%s: cluster credentials have expired.\nRefresh them (e.g. 'aws sso login') and try again.\n\n%v", msg, err)
+	case errors.Is(err, kube.ErrPodNotFound):
+		log.Fatalf("%s: %v\nCheck that namespace %q on %s is correct and the deployment is healthy:\n  kubectl --context %s -n %s get pods", msg, err, c.Namespace, c.Name, c.Name, c.Namespace)
+	default:
+		log.Fatalf("%s: %v", msg, err)
+	}
+}
+
+// runWhoisAllAdmins discovers every tenant schema and writes its active admins
+// (CSV unless --format says otherwise).
+func runWhoisAllAdmins(opts *WhoisOptions) {
+	format := parseOutputFormat(opts.Format)
+	c := clusterFromEnv(opts.Context)
+
+	if opts.Explain {
+		explainWhois(c, opts.Context, tenantSchemasQuery)
+		fmt.Println()
+		fmt.Println("-- then, per batch of up to", allAdminsBatchSize, "schemas:")
+		sql, _ := buildAllAdminsQuery([]string{"tenant_a", "tenant_b"}, opts.IncludeInactive)
+		fmt.Println(sql)
+		return
+	}
+
+	pod := connectWhois(c)
+
+	log.Info("Discovering tenant schemas...")
+	var schemas []string
+	for _, schema := range queryPod(c, pod, tenantSchemasQuery) {
+		if !safeIdentifier.MatchString(schema) {
+			log.Warnf("Skipping tenant schema with unexpected name: %q", schema)
+			continue
+		}
+		schemas = append(schemas, schema)
+	}
+	log.Infof("Found %d tenant schemas", len(schemas))
+
+	var out io.Writer = os.Stdout
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", opts.Output, err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	table := output.Table{Columns: []string{"TENANT ID", "EMAIL"}}
+	for _, admin := range queryAdmins(c, pod, schemas, opts.IncludeInactive) {
+		table.AddRow(admin.Tenant, admin.Email)
+	}
+
+	if err := output.Write(out, format, table); err != nil {
+		log.Fatalf("Failed to write admins: %v", err)
+	}
+
+	if opts.Output != "" {
+		log.Infof("Wrote %d admin(s) across %d tenant(s) to %s", len(table.Rows), len(schemas), opts.Output)
 	}
 }
 
-func findByEmail(c *kube.Cluster, pod, fragment string) {
-	fragment = strings.NewReplacer("'", "", `"`, "", `;`, "", `\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(fragment)
+// tenantAdmin is one (tenant, admin email) row of an admin query.
+type tenantAdmin struct {
+	Tenant string
+	Email  string
+}
 
-	sql := fmt.Sprintf(
-		`SELECT email, tenant_id, active FROM public.user_tenant_mapping WHERE email LIKE '%%%s%%' ORDER BY email;`,
-		fragment,
+// queryAdmins fetches the admins of the given tenant schemas, batching them
+// into UNION ALL queries of up to allAdminsBatchSize schemas.
+func queryAdmins(c *kube.Cluster, pod string, schemas []string, includeInactive bool) []tenantAdmin {
+	var admins []tenantAdmin
+	for start := 0; start < len(schemas); start += allAdminsBatchSize {
+		end := min(start+allAdminsBatchSize, len(schemas))
+		sql, err := buildAllAdminsQuery(schemas[start:end], includeInactive)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Debugf("Querying tenants %d-%d of %d", start+1, end, len(schemas))
+		for _, line := range queryPod(c, pod, sql) {
+			tenant, email, ok := strings.Cut(line, "\t")
+			if !ok {
+				log.Warnf("Skipping malformed row: %q", line)
+				continue
+			}
+			admins = append(admins, tenantAdmin{Tenant: tenant, Email: email})
+		}
+	}
+	return admins
+}
+
+// adminFilter returns the WHERE clause selecting admins of a tenant schema.
+func adminFilter(includeInactive bool) string {
+	filter := `role = 'ADMIN' AND email NOT LIKE 'api_key__%'`
+	if !includeInactive {
+		filter += " AND is_active = true"
+	}
+	return filter
+}
+
+// buildAllAdminsQuery combines the per-tenant admin query for each schema into
+// a single UNION ALL query returning (tenant_id, email) rows.
+func buildAllAdminsQuery(schemas []string, includeInactive bool) (string, error) {
+	parts := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		if !safeIdentifier.MatchString(schema) {
+			return "", fmt.Errorf("invalid tenant schema: %q", schema)
+		}
+		parts = append(parts, fmt.Sprintf(
+			`SELECT '%s' AS tenant_id, email FROM "%s"."user" WHERE %s`,
+			schema, schema, adminFilter(includeInactive),
+		))
+	}
+	return strings.Join(parts, " UNION ALL ") + " ORDER BY tenant_id, email;", nil
+}
+
+// explainWhois prints what runWhois would execute without touching the cluster.
+func explainWhois(c *kube.Cluster, ctx, sql string) {
+	fmt.Printf("Context:   %s (KUBE_CTX_%s)\n", ctx, strings.ToUpper(ctx))
+	fmt.Printf("Cluster:   %s (%s)\n", c.Name, c.Region)
+	fmt.Printf("Namespace: %s\n", c.Namespace)
+	fmt.Printf("Pod:       first pod matching %q\n", whoisPodSubstring)
+	fmt.Println("Command:   pginto -A -t -F '\\t' -c <query>")
+	fmt.Println()
+	fmt.Println(sql)
+}
+
+// escapeLikeFragment strips quoting characters and escapes LIKE wildcards so the
+// fragment is matched literally.
+func escapeLikeFragment(fragment string) string {
+	return strings.NewReplacer("'", "", `"`, "", `;`, "", `\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(fragment)
+}
+
+// buildEmailQuery returns the SQL used to search user_tenant_mapping by email
+// fragment, optionally restricted to active mappings.
+func buildEmailQuery(fragment string, activeOnly bool) string {
+	where := fmt.Sprintf("email LIKE '%%%s%%'", escapeLikeFragment(fragment))
+	if activeOnly {
+		where += " AND active = true"
+	}
+	return fmt.Sprintf(
+		`SELECT email, tenant_id, active FROM public.user_tenant_mapping WHERE %s ORDER BY email;`,
+		where,
 	)
+}
+
+// buildTenantAdminsQuery returns the SQL used to list the admins of a tenant
+// schema. Deactivated admins are excluded unless includeInactive is set.
+func buildTenantAdminsQuery(tenantID string, includeInactive bool) (string, error) {
+	if !safeIdentifier.MatchString(tenantID) {
+		return "", fmt.Errorf("invalid tenant ID: %q (must be alphanumeric, hyphens, underscores only)", tenantID)
+	}
+	return fmt.Sprintf(
+		`SELECT email FROM "%s"."user" WHERE %s ORDER BY email;`,
+		tenantID, adminFilter(includeInactive),
+	), nil
+}
 
-	log.Infof("Searching for emails matching '%%%s%%'...", fragment)
+func findByEmail(c *kube.Cluster, pod, fragment, sql string, format output.Format) {
+	log.Infof("Searching for emails matching '%%%s%%'...", escapeLikeFragment(fragment))
 	lines := queryPod(c, pod, sql)
-	if len(lines) == 0 {
+	if len(lines) == 0 && format == output.FormatTable {
 		fmt.Println("No results found.")
 		return
 	}
 
-	fmt.Println()
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "EMAIL\tTENANT ID\tACTIVE")
-	_, _ = fmt.Fprintln(w, "-----\t---------\t------")
+	table := output.Table{Columns: []string{"EMAIL", "TENANT ID", "ACTIVE"}}
 	for _, line := range lines {
-		_, _ = fmt.Fprintln(w, line)
+		table.AddRow(strings.Split(line, "\t")...)
 	}
-	_ = w.Flush()
+	writeWhoisTable(table, format)
 }
 
-func findAdminsByTenant(c *kube.Cluster, pod, tenantID string) {
-	if !safeIdentifier.MatchString(tenantID) {
-		log.Fatalf("Invalid tenant ID: %q (must be alphanumeric, hyphens, underscores only)", tenantID)
+// findByEmailWithAdmins searches by email fragment, then looks up the admins
+// of every tenant the matching users belong to and prints one report grouped
+// by tenant.
+func findByEmailWithAdmins(c *kube.Cluster, pod, fragment, sql string, includeInactive bool, format output.Format) {
+	log.Infof("Searching for emails matching '%%%s%%'...", escapeLikeFragment(fragment))
+	matches := queryPod(c, pod, sql)
+	if len(matches) == 0 && format == output.FormatTable {
+		fmt.Println("No results found.")
+		return
 	}
 
-	sql := fmt.Sprintf(
-		`SELECT email FROM "%s"."user" WHERE role = 'ADMIN' AND is_active = true AND email NOT LIKE 'api_key__%%' ORDER BY email;`,
-		tenantID,
-	)
+	var tenants []string
+	seen := make(map[string]bool)
+	for _, line := range matches {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || seen[fields[1]] {
+			continue
+		}
+		seen[fields[1]] = true
+		if !safeIdentifier.MatchString(fields[1]) {
+			log.Warnf("Skipping tenant with unexpected name: %q", fields[1])
+			continue
+		}
+		tenants = append(tenants, fields[1])
+	}
+
+	log.Infof("Fetching admins for %d tenant(s)...", len(tenants))
+	admins := queryAdmins(c, pod, tenants, includeInactive)
+	writeWhoisTable(buildTenantReport(matches, admins), format)
+}
+
+// buildTenantReport merges email search rows (email, tenant_id, active) with
+// the admins of those tenants into one table sorted by tenant, then email.
+// A user that both matched the search and is an admin appears once.
+func buildTenantReport(matches []string, admins []tenantAdmin) output.Table {
+	type key struct{ tenant, email string }
+	type entry struct{ matched, admin bool }
+	entries := make(map[key]*entry)
+	get := func(k key) *entry {
+		if entries[k] == nil {
+			entries[k] = &entry{}
+		}
+		return entries[k]
+	}
+	for _, line := range matches {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		get(key{tenant: fields[1], email: fields[0]}).matched = true
+	}
+	for _, a := range admins {
+		get(key{tenant: a.Tenant, email: a.Email}).admin = true
+	}
+
+	keys := make([]key, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].tenant != keys[j].tenant {
+			return keys[i].tenant < keys[j].tenant
+		}
+		return keys[i].email < keys[j].email
+	})
 
+	table := output.Table{Columns: []string{"TENANT ID", "EMAIL", "MATCHED", "ADMIN"}}
+	for _, k := range keys {
+		e := entries[k]
+		table.AddRow(k.tenant, k.email, strconv.FormatBool(e.matched), strconv.FormatBool(e.admin))
+	}
+	return table
+}
+
+func findAdminsByTenant(c *kube.Cluster, pod, tenantID, sql string, format output.Format) {
 	log.Infof("Fetching admin emails for %s...", tenantID)
 	lines := queryPod(c, pod, sql)
-	if len(lines) == 0 {
+	if len(lines) == 0 && format == output.FormatTable {
 		fmt.Println("No admin users found for this tenant.")
 		return
 	}
 
-	fmt.Println()
-	fmt.Println("EMAIL")
-	fmt.Println("-----")
+	table := output.Table{Columns: []string{"EMAIL"}}
 	for _, line := range lines {
-		fmt.Println(line)
+		table.AddRow(line)
+	}
+	writeWhoisTable(table, format)
+}
+
+// writeWhoisTable prints lookup results to stdout, separated from the log
+// lines above them when printing a table.
+func writeWhoisTable(table output.Table, format output.Format) {
+	if format == output.FormatTable {
+		fmt.Println()
+	}
+	if err := output.Write(os.Stdout, format, table); err != nil {
+		log.Fatalf("Failed to write results: %v", err)
+	}
+}
+
+// parseOutputFormat validates a --format flag value, exiting on error.
+func parseOutputFormat(value string) output.Format {
+	format, err := output.ParseFormat(value)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
+	return format
 }
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildEmailQuery_escapesFragment(t *testing.T) {
+	sql := buildEmailQuery(`o'brien_%`, false)
+
+	if !strings.Contains(sql, `LIKE '%obrien\_\%%'`) {
+		t.Errorf("expected escaped LIKE pattern, got:\n%s", sql)
+	}
+	if strings.Contains(sql, "o'brien") {
+		t.Errorf("expected single quote to be stripped, got:\n%s", sql)
+	}
+	if strings.Contains(sql, "active = true") {
+		t.Errorf("expected no active filter by default, got:\n%s", sql)
+	}
+
+	if sql := buildEmailQuery("chris", true); !strings.Contains(sql, "AND active = true ORDER BY") {
+		t.Errorf("expected active filter with activeOnly, got:\n%s", sql)
+	}
+}
+
+func TestBuildTenantAdminsQuery(t *testing.T) {
+	sql, err := buildTenantAdminsQuery("tenant_abc-123", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(sql, `FROM "tenant_abc-123"."user"`) {
+		t.Errorf("expected quoted tenant schema, got:\n%s", sql)
+	}
+	if !strings.Contains(sql, "is_active = true") {
+		t.Errorf("expected active filter by default, got:\n%s", sql)
+	}
+
+	sql, err = buildTenantAdminsQuery("tenant_abc-123", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(sql, "is_active") {
+		t.Errorf("expected no active filter with includeInactive, got:\n%s", sql)
+	}
+
+	if _, err := buildTenantAdminsQuery(`tenant_x"; DROP TABLE user; --`, false); err == nil {
+		t.Error("expected error for unsafe tenant ID")
+	}
+}
+
+func TestBuildAllAdminsQuery(t *testing.T) {
+	sql, err := buildAllAdminsQuery([]string{"tenant_a", "tenant_b"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(sql, " UNION ALL ") != 1 {
+		t.Errorf("expected one UNION ALL, got:\n%s", sql)
+	}
+	for _, want := range []string{`SELECT 'tenant_a' AS tenant_id`, `FROM "tenant_b"."user"`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in query, got:\n%s", want, sql)
+		}
+	}
+
+	if _, err := buildAllAdminsQuery([]string{"tenant_a", `x"."user"; --`}, false); err == nil {
+		t.Error("expected error for unsafe schema name")
+	}
+}
+
+func TestBuildTenantReport(t *testing.T) {
+	matches := []string{
+		"chris@b.com\ttenant_b\tt",
+		"chris@a.com\ttenant_a\tf",
+	}
+	admins := []tenantAdmin{
+		{Tenant: "tenant_a", Email: "root@a.com"},
+		{Tenant: "tenant_a", Email: "chris@a.com"},
+		{Tenant: "tenant_b", Email: "admin@b.com"},
+	}
+
+	table := buildTenantReport(matches, admins)
+
+	want := [][]string{
+		{"tenant_a", "chris@a.com", "true", "true"},
+		{"tenant_a", "root@a.com", "false", "true"},
+		{"tenant_b", "admin@b.com", "false", "true"},
+		{"tenant_b", "chris@b.com", "true", "false"},
+	}
+	if len(table.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d: %v", len(want), len(table.Rows), table.Rows)
+	}
+	for i, row := range want {
+		if strings.Join(table.Rows[i], ",") != strings.Join(row, ",") {
+			t.Errorf("row %d = %v, want %v", i, table.Rows[i], row)
+		}
+	}
+}
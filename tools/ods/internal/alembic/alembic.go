@@ -1,11 +1,14 @@
 package alembic
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -53,12 +56,17 @@ func FindAlembicBinary() (string, error) {
 // run via docker exec on a container that has alembic installed (e.g.,
 // api_server).
 func Run(args []string, schema Schema) error {
+	return run(args, schema, os.Stdout)
+}
+
+// run is Run with alembic's stdout sent to stdout.
+func run(args []string, schema Schema, stdout io.Writer) error {
 	// Check if we need to run via docker exec.
 	if shouldUseDockerExec() {
-		return runViaDockerExec(args, schema)
+		return runViaDockerExec(args, schema, stdout)
 	}
 
-	return runLocally(args, schema)
+	return runLocally(args, schema, stdout, buildAlembicEnv(true))
 }
 
 // shouldUseDockerExec determines if we should run alembic via docker exec.
@@ -78,8 +86,8 @@ func shouldUseDockerExec() bool {
 	return !docker.IsPortExposed(container, "5432")
 }
 
-// runLocally runs alembic on the local machine.
-func runLocally(args []string, schema Schema) error {
+// runLocally runs alembic on the local machine with the given environment.
+func runLocally(args []string, schema Schema, stdout io.Writer, env []string) error {
 	backendDir, err := paths.BackendDir()
 	if err != nil {
 		return fmt.Errorf("failed to find backend directory: %w", err)
@@ -99,19 +107,19 @@ func runLocally(args []string, schema Schema) error {
 
 	cmd := exec.Command(alembic, cmdArgs...)
 	cmd.Dir = backendDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
 	// Pass through POSTGRES_* environment variables.
-	cmd.Env = buildAlembicEnv()
+	cmd.Env = env
 
 	return cmd.Run()
 }
 
 // runViaDockerExec runs alembic inside a Docker container that has network
 // access.
-func runViaDockerExec(args []string, schema Schema) error {
+func runViaDockerExec(args []string, schema Schema, stdout io.Writer) error {
 	// Find a container with alembic installed (api_server).
 	container, err := findAlembicContainer()
 	if err != nil {
@@ -141,7 +149,7 @@ func runViaDockerExec(args []string, schema Schema) error {
 	dockerArgs = append(dockerArgs, alembicArgs...)
 
 	cmd := exec.Command("docker", dockerArgs...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
@@ -158,9 +166,9 @@ var legacyAlembicContainerNames = []string{
 
 // buildAlembicEnv builds the environment for running alembic.
 // It inherits the current environment and ensures POSTGRES_* variables are set.
-// If POSTGRES_HOST is not explicitly set, it attempts to detect the PostgreSQL
-// container IP address automatically.
-func buildAlembicEnv() []string {
+// If POSTGRES_HOST is not explicitly set and detectHost is true, it attempts to
+// detect the PostgreSQL container IP address automatically.
+func buildAlembicEnv(detectHost bool) []string {
 	env := os.Environ()
 
 	// Get postgres config (which reads from env with defaults)
@@ -168,7 +176,7 @@ func buildAlembicEnv() []string {
 
 	// If POSTGRES_HOST is not explicitly set, try to detect the host
 	host := config.Host
-	if os.Getenv("POSTGRES_HOST") == "" {
+	if detectHost && os.Getenv("POSTGRES_HOST") == "" {
 		if detectedHost := detectPostgresHost(); detectedHost != "" {
 			host = detectedHost
 		}
@@ -252,16 +260,154 @@ func Downgrade(revision string, schema Schema) error {
 	return Run([]string{"downgrade", revision}, schema)
 }
 
+// UpgradeSQL prints the SQL for upgrading to the specified revision without
+// connecting to the database (alembic offline mode). It always runs locally
+// and doesn't need a running PostgreSQL container.
+func UpgradeSQL(revision string, schema Schema) error {
+	if revision == "" {
+		revision = "head"
+	}
+	return runOffline([]string{"upgrade", revision, "--sql"}, schema)
+}
+
+// DowngradeSQL prints the SQL for a downgrade without connecting to the
+// database. Offline downgrades need an explicit "from:to" revision range.
+func DowngradeSQL(revision string, schema Schema) error {
+	return runOffline([]string{"downgrade", revision, "--sql"}, schema)
+}
+
+// runOffline runs alembic locally in offline (--sql) mode. The POSTGRES_*
+// settings are still passed for alembic's config, but no container is looked
+// up.
+func runOffline(args []string, schema Schema) error {
+	return runLocally(args, schema, os.Stdout, buildAlembicEnv(false))
+}
+
 // Current shows the current alembic revision.
 func Current(schema Schema) error {
 	return Run([]string{"current"}, schema)
 }
 
-// History shows the alembic migration history.
-func History(schema Schema, verbose bool) error {
+// CurrentRevision returns the database's current revision as reported by
+// alembic current (e.g. "abc123 (head)"), or "" if it has none.
+func CurrentRevision(schema Schema) (string, error) {
+	var out bytes.Buffer
+	if err := run([]string{"current"}, schema, &out); err != nil {
+		return "", err
+	}
+	return parseCurrent(out.String()), nil
+}
+
+// HeadRevisions returns the head revision IDs of the migration scripts.
+func HeadRevisions(schema Schema) ([]string, error) {
+	var out bytes.Buffer
+	if err := run([]string{"heads"}, schema, &out); err != nil {
+		return nil, err
+	}
+	var heads []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "INFO") {
+			continue
+		}
+		heads = append(heads, RevisionID(line))
+	}
+	return heads, nil
+}
+
+// Check fails if the database is behind the migration heads, or if the models
+// have changes that no migration covers (alembic check).
+func Check(schema Schema) error {
+	current, err := CurrentRevision(schema)
+	if err != nil {
+		return fmt.Errorf("failed to get current revision: %w", err)
+	}
+	heads, err := HeadRevisions(schema)
+	if err != nil {
+		return fmt.Errorf("failed to get head revisions: %w", err)
+	}
+	if id := RevisionID(current); !slices.Contains(heads, id) {
+		if id == "" {
+			id = "no revision"
+		}
+		return fmt.Errorf("database is at %s but the migration head is %s; run 'ods db upgrade'", id, strings.Join(heads, ", "))
+	}
+	if err := Run([]string{"check"}, schema); err != nil {
+		return fmt.Errorf("models have changes not covered by a migration; run 'ods db revision --autogenerate': %w", err)
+	}
+	return nil
+}
+
+// RevisionID returns the bare revision ID from a line such as
+// "abc123 (head)".
+func RevisionID(revision string) string {
+	if fields := strings.Fields(revision); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// parseCurrent extracts the revision line from alembic current output,
+// skipping any log lines alembic writes to stdout.
+func parseCurrent(output string) string {
+	revision := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "INFO") {
+			continue
+		}
+		revision = line
+	}
+	return revision
+}
+
+// Heads shows the head revisions of the migration scripts.
+func Heads(schema Schema) error {
+	return Run([]string{"heads"}, schema)
+}
+
+// Merge creates a migration that merges the given revisions (usually two
+// heads) into one. Like Revision, it always runs locally.
+func Merge(revisions []string, message string, schema Schema) error {
+	if len(revisions) < 2 {
+		return fmt.Errorf("merge needs at least two revisions, got %d", len(revisions))
+	}
+	args := []string{"merge"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	args = append(args, revisions...)
+	return runLocally(args, schema, os.Stdout, buildAlembicEnv(true))
+}
+
+// Revision creates a new migration script. With autogenerate, alembic fills
+// it in by comparing the models with the database. It always runs locally so
+// the script is written to the checkout rather than inside a container.
+func Revision(message string, autogenerate bool, schema Schema) error {
+	return runLocally(revisionArgs(message, autogenerate), schema, os.Stdout, buildAlembicEnv(true))
+}
+
+// revisionArgs builds the alembic revision arguments.
+func revisionArgs(message string, autogenerate bool) []string {
+	args := []string{"revision"}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	if autogenerate {
+		args = append(args, "--autogenerate")
+	}
+	return args
+}
+
+// History shows the alembic migration history. A non-empty revRange
+// ("start:end", either side optional) limits it to those revisions.
+func History(schema Schema, verbose bool, revRange string) error {
 	args := []string{"history"}
 	if verbose {
 		args = append(args, "-v")
 	}
+	if revRange != "" {
+		args = append(args, "-r", revRange)
+	}
 	return Run(args, schema)
 }
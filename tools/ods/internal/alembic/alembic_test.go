@@ -0,0 +1,30 @@
+package alembic
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCurrent(t *testing.T) {
+	tests := []struct {
+		name, output, want string
+	}{
+		{"head", "INFO  [alembic.runtime.migration] Context impl PostgresqlImpl.\nabc123def456 (head)\n", "abc123def456 (head)"},
+		{"behind", "abc123def456\n", "abc123def456"},
+		{"empty", "INFO  [alembic.runtime.migration] Will assume transactional DDL.\n", ""},
+	}
+	for _, tt := range tests {
+		if got := parseCurrent(tt.output); got != tt.want {
+			t.Errorf("%s: parseCurrent() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRevisionArgs(t *testing.T) {
+	if got, want := revisionArgs("add table", true), []string{"revision", "-m", "add table", "--autogenerate"}; !slices.Equal(got, want) {
+		t.Errorf("revisionArgs(autogenerate) = %v, want %v", got, want)
+	}
+	if got, want := revisionArgs("", false), []string{"revision"}; !slices.Equal(got, want) {
+		t.Errorf("revisionArgs(empty) = %v, want %v", got, want)
+	}
+}
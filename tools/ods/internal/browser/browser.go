@@ -0,0 +1,38 @@
+// Package browser opens URLs in the user's default web browser.
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// ErrNoOpener is returned when no command for opening a browser is available.
+var ErrNoOpener = errors.New("no browser opener available")
+
+// Open launches the default browser on url without waiting for it to exit.
+func Open(url string) error {
+	name, args := command(runtime.GOOS, url)
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%w (%s not found)", ErrNoOpener, name)
+	}
+	if err := exec.Command(name, args...).Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return nil
+}
+
+// command returns the program and arguments that open url on goos.
+func command(goos, url string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		// The empty argument is the window title; without it, start would
+		// treat a quoted URL as the title
+		return "cmd", []string{"/c", "start", "", url}
+	default:
+		return "xdg-open", []string{url}
+	}
+}
@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	const url = "https://github.com/onyx-dot-app/onyx/pull/1"
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{"darwin", "open", []string{url}},
+		{"linux", "xdg-open", []string{url}},
+		{"freebsd", "xdg-open", []string{url}},
+		{"windows", "cmd", []string{"/c", "start", "", url}},
+	}
+	for _, tt := range tests {
+		name, args := command(tt.goos, url)
+		if name != tt.wantName || !slices.Equal(args, tt.wantArgs) {
+			t.Errorf("command(%q) = %s %v, want %s %v", tt.goos, name, args, tt.wantName, tt.wantArgs)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package cache provides concurrency-safe on-disk cache entries.
+//
+// An entry is a directory that is populated in a temporary sibling directory
+// and atomically renamed into place, so readers only ever see fully-populated
+// entries. Writers for the same entry are serialized with a lock file.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// lockPollInterval is how often a waiting writer retries the lock.
+	lockPollInterval = 250 * time.Millisecond
+
+	// StaleLockAge is how old a lock file must be before it is assumed to be
+	// left over from a crashed writer and removed.
+	StaleLockAge = 30 * time.Minute
+
+	// lockRefreshInterval is how often a held lock's modification time is
+	// bumped so long-running holders are never mistaken for stale ones.
+	lockRefreshInterval = StaleLockAge / 6
+)
+
+// Lock acquires an exclusive lock file at path, waiting up to timeout for
+// another holder to release it. Lock files older than StaleLockAge are
+// broken; a held lock is refreshed well before it gets that old. The returned
+// function releases the lock.
+func Lock(path string, timeout time.Duration) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	// The token identifies this holder, so a lock re-created by another
+	// process after breaking a stale one is never mistaken for ours.
+	token := fmt.Sprintf("%d %d", os.Getpid(), time.Now().UnixNano())
+	deadline := time.Now().Add(timeout)
+	logged := false
+	brokeStale := false
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = f.WriteString(token)
+			_ = f.Close()
+			if brokeStale {
+				// Another waiter may have seen the same stale lock and removed
+				// the one we just created; wait a beat and make sure it's ours.
+				time.Sleep(lockPollInterval)
+				if !ownsLock(path, token) {
+					brokeStale = false
+					continue
+				}
+			}
+			return holdLock(path, token), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", path, err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > StaleLockAge {
+			log.Warnf("Removing stale lock %s", path)
+			_ = os.Remove(path)
+			brokeStale = true
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for lock %s", timeout, path)
+		}
+		if !logged {
+			log.Infof("Waiting for another process holding %s...", path)
+			logged = true
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// LockHolder returns the pid recorded in the lock file at path, or "" if it
+// can't be read.
+func LockHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	pid, _, _ := strings.Cut(strings.TrimSpace(string(data)), " ")
+	return pid
+}
+
+// ownsLock reports whether the lock file at path still holds token.
+func ownsLock(path, token string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && string(data) == token
+}
+
+// holdLock keeps the lock file at path fresh until the returned release
+// function is called. Release only removes the file if it is still ours.
+func holdLock(path, token string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !ownsLock(path, token) {
+					log.Warnf("Lost lock %s to another process", path)
+					return
+				}
+				now := time.Now()
+				_ = os.Chtimes(path, now, now)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			if ownsLock(path, token) {
+				_ = os.Remove(path)
+			}
+		})
+	}
+}
+
+// Prune deletes everything under root, leaving root itself in place. Top-level
+// entries containing a lock that is still held (not older than StaleLockAge)
+// are skipped so a concurrent writer isn't pulled out from under; their names
+// are returned. A missing root has nothing to prune.
+func Prune(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var skipped []string
+	for _, entry := range entries {
+		path := filepath.Join(root, entry.Name())
+		if holdsLiveLock(path) {
+			skipped = append(skipped, entry.Name())
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return skipped, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return skipped, nil
+}
+
+// holdsLiveLock reports whether path is, or contains, a lock file that is not
+// yet stale.
+func holdsLiveLock(path string) bool {
+	live := false
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".lock" {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && time.Since(info.ModTime()) <= StaleLockAge {
+			live = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return live
+}
+
+// Exists reports whether a populated cache entry exists at dir.
+func Exists(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// Populate ensures the cache entry at dir exists, calling fill to populate a
+// temporary directory if it does not. The temporary directory is renamed to
+// dir only after fill succeeds, so a failed or interrupted fill never leaves
+// a partial entry behind. Concurrent callers for the same dir wait for each
+// other; later callers reuse the entry created by the first.
+//
+// Populate returns true if the entry already existed.
+func Populate(dir string, lockTimeout time.Duration, fill func(tmpDir string) error) (bool, error) {
+	if Exists(dir) {
+		return true, nil
+	}
+
+	unlock, err := Lock(dir+".lock", lockTimeout)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	// Another writer may have finished while we waited for the lock
+	if Exists(dir) {
+		return true, nil
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".tmp-")
+	if err != nil {
+		return false, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	if err := fill(tmpDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return false, err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return false, fmt.Errorf("failed to move cache entry into place: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPopulate_FillsOnce(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "entry")
+
+	var fills atomic.Int32
+	fill := func(tmp string) error {
+		fills.Add(1)
+		// Give concurrent callers time to pile up on the lock
+		time.Sleep(50 * time.Millisecond)
+		return os.WriteFile(filepath.Join(tmp, "data"), []byte("ok"), 0644)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Populate(dir, 5*time.Second, fill); err != nil {
+				t.Errorf("Populate failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := fills.Load(); n != 1 {
+		t.Errorf("fill called %d times, want 1", n)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "data")); err != nil || string(data) != "ok" {
+		t.Errorf("cache entry not populated: %q, %v", data, err)
+	}
+	if _, err := os.Stat(dir + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be removed, stat err = %v", err)
+	}
+}
+
+func TestPopulate_FailureLeavesNoEntry(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "entry")
+
+	_, err := Populate(dir, time.Second, func(tmp string) error {
+		_ = os.WriteFile(filepath.Join(tmp, "partial"), []byte("x"), 0644)
+		return errors.New("download failed")
+	})
+	if err == nil {
+		t.Fatal("expected fill error to be returned")
+	}
+	if Exists(dir) {
+		t.Error("expected no cache entry after failed fill")
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 0 {
+		t.Errorf("expected temp dir and lock to be cleaned up, found %d entries", len(entries))
+	}
+}
+
+func TestLock_TimesOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+
+	unlock, err := Lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	defer unlock()
+
+	if _, err := Lock(path, 300*time.Millisecond); err == nil {
+		t.Error("expected second Lock to time out")
+	}
+}
+
+func TestLock_ReleaseKeepsOtherHoldersLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+
+	unlock, err := Lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if pid := LockHolder(path); pid != strconv.Itoa(os.Getpid()) {
+		t.Errorf("LockHolder = %q, want %d", pid, os.Getpid())
+	}
+
+	// Simulate another process breaking our lock as stale and re-creating it
+	if err := os.WriteFile(path, []byte("999999 1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	if pid := LockHolder(path); pid != "999999" {
+		t.Errorf("expected the other holder's lock to survive release, LockHolder = %q", pid)
+	}
+}
+
+func TestPrune_SkipsEntriesWithLiveLocks(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{
+		filepath.Join(root, "traces", "abc", "trace.zip"),
+		filepath.Join(root, "playwright-artifacts", "123", "admin", "page.png"),
+		filepath.Join(root, "playwright-artifacts", "456", "admin.lock"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	skipped, err := Prune(root)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "playwright-artifacts" {
+		t.Errorf("skipped = %v, want [playwright-artifacts]", skipped)
+	}
+	if _, err := os.Stat(filepath.Join(root, "traces")); !os.IsNotExist(err) {
+		t.Errorf("expected traces to be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("expected root to be kept: %v", err)
+	}
+}
+
+func TestPrune_MissingRoot(t *testing.T) {
+	if _, err := Prune(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("Prune of a missing root failed: %v", err)
+	}
+}
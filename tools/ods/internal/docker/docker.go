@@ -7,8 +7,52 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
 )
 
+// PostgresContainerEnv names an env var that, when set, forces
+// FindPostgresContainer to use that container instead of detecting one. It is
+// an escape hatch for deployments with non-standard compose project names.
+const PostgresContainerEnv = "ODS_POSTGRES_CONTAINER"
+
+// logOverrideOnce keeps repeated lookups in one command from logging the
+// override more than once.
+var logOverrideOnce sync.Once
+
+var dryRun bool
+
+// SetDryRun makes Exec, ExecWithEnv, ExecTTY, CopyFromContainer, and
+// CopyToContainer log the docker command they would run and succeed without
+// running it. Read-only helpers are unaffected. Called from the root command's
+// PersistentPreRun.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// DryRun reports whether docker commands are being logged instead of run.
+func DryRun() bool {
+	return dryRun
+}
+
+// skipForDryRun logs args as a docker command and reports whether it should be
+// skipped because dry-run is enabled. Values of password env vars are redacted.
+func skipForDryRun(args []string) bool {
+	if !dryRun {
+		return false
+	}
+	shown := make([]string, len(args))
+	for i, arg := range args {
+		if key, _, ok := strings.Cut(arg, "="); ok && i > 0 && args[i-1] == "-e" && strings.Contains(key, "PASSWORD") {
+			arg = key + "=***"
+		}
+		shown[i] = arg
+	}
+	log.Infof("[DRY RUN] docker %s", strings.Join(shown, " "))
+	return true
+}
+
 // legacyPostgresContainerNames are fallback names tried after the
 // project-specific name.
 var legacyPostgresContainerNames = []string{
@@ -21,8 +65,18 @@ var legacyPostgresContainerNames = []string{
 
 // FindPostgresContainer finds a running PostgreSQL container. It tries the
 // project-specific name first, then legacy names, then falls back to searching
-// by image.
+// by image. If ODS_POSTGRES_CONTAINER is set, that container is used as is.
 func FindPostgresContainer(projectName string) (string, error) {
+	if name := os.Getenv(PostgresContainerEnv); name != "" {
+		logOverrideOnce.Do(func() {
+			log.Infof("Using PostgreSQL container %s from %s", name, PostgresContainerEnv)
+		})
+		if !isContainerRunning(name) {
+			return "", fmt.Errorf("container %q from %s is not running: %w", name, PostgresContainerEnv, ErrContainerNotFound)
+		}
+		return name, nil
+	}
+
 	projectContainer := fmt.Sprintf("%s-relational_db-1", projectName)
 	if isContainerRunning(projectContainer) {
 		return projectContainer, nil
@@ -52,7 +106,7 @@ func FindPostgresContainer(projectName string) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("no running PostgreSQL container found for project %q; try: ods compose dev", projectName)
+	return "", fmt.Errorf("no running PostgreSQL container for project %q (try: ods compose dev): %w", projectName, ErrContainerNotFound)
 }
 
 // isContainerRunning checks if a container with the given name is running.
@@ -65,14 +119,21 @@ func isContainerRunning(name string) bool {
 	return strings.TrimSpace(string(output)) == "true"
 }
 
-// Exec runs a command inside a Docker container.
+// Exec runs a command inside a Docker container. Output is streamed to the
+// terminal; failures are returned as *ExecError.
 func Exec(container string, args ...string) error {
 	dockerArgs := append([]string{"exec", "-i", container}, args...)
+	if skipForDryRun(dockerArgs) {
+		return nil
+	}
 	cmd := exec.Command("docker", dockerArgs...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return &ExecError{Container: container, Args: args, Err: err}
+	}
+	return nil
 }
 
 // ExecWithEnv runs a command inside a Docker container with environment
@@ -84,15 +145,46 @@ func ExecWithEnv(container string, env map[string]string, args ...string) error
 	}
 	dockerArgs = append(dockerArgs, container)
 	dockerArgs = append(dockerArgs, args...)
+	if skipForDryRun(dockerArgs) {
+		return nil
+	}
 
 	cmd := exec.Command("docker", dockerArgs...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return &ExecError{Container: container, Args: args, Err: err}
+	}
+	return nil
+}
+
+// ExecTTY runs an interactive command inside a Docker container with a
+// pseudo-terminal attached, for shells like psql. Failures are returned as
+// *ExecError.
+func ExecTTY(container string, env map[string]string, args ...string) error {
+	dockerArgs := []string{"exec", "-it"}
+	for k, v := range env {
+		dockerArgs = append(dockerArgs, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	dockerArgs = append(dockerArgs, container)
+	dockerArgs = append(dockerArgs, args...)
+	if skipForDryRun(dockerArgs) {
+		return nil
+	}
+
+	cmd := exec.Command("docker", dockerArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return &ExecError{Container: container, Args: args, Err: err}
+	}
+	return nil
 }
 
 // ExecOutput runs a command inside a Docker container and returns its output.
+// Failures are returned as *ExecError with the captured stderr.
 func ExecOutput(container string, args ...string) (string, error) {
 	dockerArgs := append([]string{"exec", "-i", container}, args...)
 	cmd := exec.Command("docker", dockerArgs...)
@@ -101,14 +193,18 @@ func ExecOutput(container string, args ...string) (string, error) {
 	cmd.Stderr = &stderr
 	err := cmd.Run()
 	if err != nil {
-		return "", fmt.Errorf("%w: %s", err, stderr.String())
+		return "", &ExecError{Container: container, Args: args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
 	}
 	return stdout.String(), nil
 }
 
 // CopyFromContainer copies a file from a container to the host.
 func CopyFromContainer(container, src, dst string) error {
-	cmd := exec.Command("docker", "cp", fmt.Sprintf("%s:%s", container, src), dst)
+	args := []string{"cp", fmt.Sprintf("%s:%s", container, src), dst}
+	if skipForDryRun(args) {
+		return nil
+	}
+	cmd := exec.Command("docker", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -116,7 +212,11 @@ func CopyFromContainer(container, src, dst string) error {
 
 // CopyToContainer copies a file from the host to a container.
 func CopyToContainer(container, src, dst string) error {
-	cmd := exec.Command("docker", "cp", src, fmt.Sprintf("%s:%s", container, dst))
+	args := []string{"cp", src, fmt.Sprintf("%s:%s", container, dst)}
+	if skipForDryRun(args) {
+		return nil
+	}
+	cmd := exec.Command("docker", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -0,0 +1,16 @@
+package docker
+
+import "testing"
+
+func TestDryRun_skipsMutatingCommands(t *testing.T) {
+	SetDryRun(true)
+	t.Cleanup(func() { SetDryRun(false) })
+
+	env := map[string]string{"PGPASSWORD": "secret"}
+	if err := ExecWithEnv("ods-test-no-such-container", env, "psql", "-c", "DROP DATABASE x"); err != nil {
+		t.Errorf("ExecWithEnv in dry-run mode returned %v, want nil", err)
+	}
+	if err := CopyToContainer("ods-test-no-such-container", "/nonexistent", "/tmp/x"); err != nil {
+		t.Errorf("CopyToContainer in dry-run mode returned %v, want nil", err)
+	}
+}
@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrContainerNotFound is returned when a required container isn't running.
+// An ExecError whose stderr reports a missing container also matches it.
+var ErrContainerNotFound = errors.New("container not found")
+
+// ExecError is returned when a command run with docker exec fails. Stderr
+// holds the captured error output when it was captured (see ExecOutput).
+type ExecError struct {
+	Container string
+	Args      []string
+	Stderr    string
+	Err       error
+}
+
+func (e *ExecError) Error() string {
+	msg := fmt.Sprintf("docker exec %s %s failed: %v", e.Container, strings.Join(e.Args, " "), e.Err)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrContainerNotFound when docker couldn't find or reach the container.
+func (e *ExecError) Is(target error) bool {
+	if target != ErrContainerNotFound {
+		return false
+	}
+	lower := strings.ToLower(e.Stderr)
+	return strings.Contains(lower, "no such container") || strings.Contains(lower, "is not running")
+}
@@ -0,0 +1,18 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExecError_ContainerNotFound(t *testing.T) {
+	missing := &ExecError{Container: "onyx-relational_db-1", Stderr: "Error response from daemon: No such container: onyx-relational_db-1", Err: errors.New("exit status 1")}
+	if !errors.Is(missing, ErrContainerNotFound) {
+		t.Error("expected missing container to match ErrContainerNotFound")
+	}
+
+	failed := &ExecError{Container: "onyx-relational_db-1", Stderr: "pg_restore: error: could not open input file", Err: errors.New("exit status 1")}
+	if errors.Is(failed, ErrContainerNotFound) {
+		t.Error("command failure should not match ErrContainerNotFound")
+	}
+}
@@ -0,0 +1,58 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// healthPollInterval is how often WaitHealthy re-checks a container.
+const healthPollInterval = 2 * time.Second
+
+// Health returns a container's health status ("starting", "healthy",
+// "unhealthy"), or its state (e.g. "running", "exited") if it has no
+// healthcheck.
+func Health(container string) (string, error) {
+	cmd := exec.Command("docker", "inspect", "-f",
+		"{{if .State.Health}}{{.State.Health.Status}}{{else}}{{.State.Status}}{{end}}", container)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %w", container, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// isHealthy reports whether a status from Health means the container is
+// ready. Containers without a healthcheck are ready once running.
+func isHealthy(status string) bool {
+	return status == "healthy" || status == "running"
+}
+
+// isStopped reports whether a status from Health means the container won't
+// become healthy without being started again.
+func isStopped(status string) bool {
+	return status == "exited" || status == "dead"
+}
+
+// WaitHealthy polls a container until it is healthy, it stops, or timeout
+// elapses, and returns its last status.
+func WaitHealthy(container string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := Health(container)
+		if err != nil {
+			return "", err
+		}
+		if isHealthy(status) {
+			return status, nil
+		}
+		if isStopped(status) {
+			return status, fmt.Errorf("container %s is %s", container, status)
+		}
+		if time.Now().After(deadline) {
+			return status, fmt.Errorf("container %s still %s after %s", container, status, timeout)
+		}
+		time.Sleep(healthPollInterval)
+	}
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Container is one container of a compose project, as listed by docker ps.
+type Container struct {
+	Service string
+	Name    string
+	State   string // e.g. "running", "exited"
+	Status  string // human-readable status, including health when present
+	Ports   string
+}
+
+// psFormat is the docker ps --format template parsed by parseContainers.
+const psFormat = `{{.Label "com.docker.compose.service"}}\t{{.Names}}\t{{.State}}\t{{.Status}}\t{{.Ports}}`
+
+// ProjectContainers lists the containers of a compose project, sorted by
+// service. Stopped containers are included when all is set.
+func ProjectContainers(project string, all bool) ([]Container, error) {
+	args := []string{"ps", "--filter", "label=com.docker.compose.project=" + project, "--format", psFormat}
+	if all {
+		args = append(args, "--all")
+	}
+	output, err := exec.Command("docker", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers for project %q: %w", project, err)
+	}
+	return parseContainers(string(output)), nil
+}
+
+// parseContainers parses docker ps output written with psFormat.
+func parseContainers(output string) []Container {
+	var containers []Container
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 5)
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+		containers = append(containers, Container{
+			Service: fields[0],
+			Name:    fields[1],
+			State:   fields[2],
+			Status:  fields[3],
+			Ports:   fields[4],
+		})
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		if containers[i].Service != containers[j].Service {
+			return containers[i].Service < containers[j].Service
+		}
+		return containers[i].Name < containers[j].Name
+	})
+	return containers
+}
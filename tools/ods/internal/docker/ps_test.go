@@ -0,0 +1,24 @@
+package docker
+
+import "testing"
+
+func TestParseContainers(t *testing.T) {
+	output := "web_server\tonyx-web_server-1\trunning\tUp 2 minutes\t0.0.0.0:3000->3000/tcp\n" +
+		"api_server\tonyx-api_server-1\trunning\tUp 2 minutes (healthy)\t\n" +
+		"\n"
+
+	containers := parseContainers(output)
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d: %+v", len(containers), containers)
+	}
+	if containers[0].Service != "api_server" || containers[0].Status != "Up 2 minutes (healthy)" {
+		t.Errorf("unexpected first container: %+v", containers[0])
+	}
+	if containers[1].Ports != "0.0.0.0:3000->3000/tcp" {
+		t.Errorf("unexpected ports: %q", containers[1].Ports)
+	}
+
+	if got := parseContainers(""); len(got) != 0 {
+		t.Errorf("expected no containers for empty output, got %+v", got)
+	}
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultGHTimeout bounds a single gh invocation.
+	DefaultGHTimeout = 2 * time.Minute
+
+	// DefaultGHAttempts is the number of times a retryable gh failure is attempted.
+	DefaultGHAttempts = 3
+)
+
+// ghSleep is swapped out in tests to avoid real backoff delays.
+var ghSleep = time.Sleep
+
+// GHErrorKind classifies a failed gh invocation.
+type GHErrorKind int
+
+const (
+	// GHErrorOther is a failure that is neither auth nor network related
+	// (bad arguments, missing resources, ...). Not retried.
+	GHErrorOther GHErrorKind = iota
+	// GHErrorAuth means gh is not logged in or lacks permission. Not retried.
+	GHErrorAuth
+	// GHErrorNetwork is a transient connectivity or server-side failure. Retried.
+	GHErrorNetwork
+)
+
+// String returns a human-readable label for the error kind.
+func (k GHErrorKind) String() string {
+	switch k {
+	case GHErrorAuth:
+		return "auth"
+	case GHErrorNetwork:
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+// GHError is returned by RunGH when gh fails. Stderr holds the captured error output.
+type GHError struct {
+	Args     []string
+	Kind     GHErrorKind
+	Stderr   string
+	Attempts int
+	Err      error
+}
+
+func (e *GHError) Error() string {
+	msg := fmt.Sprintf("gh %s failed", strings.Join(ghSubcommand(e.Args), " "))
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" after %d attempts", e.Attempts)
+	}
+	msg += fmt.Sprintf(": %v", e.Err)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	if e.Kind == GHErrorAuth {
+		msg += "\nRun 'gh auth login' (or 'gh auth refresh') and try again"
+	}
+	return msg
+}
+
+func (e *GHError) Unwrap() error {
+	return e.Err
+}
+
+// GHOptions controls how RunGH executes gh.
+type GHOptions struct {
+	// Timeout bounds each attempt. Zero means DefaultGHTimeout.
+	Timeout time.Duration
+	// Attempts is the maximum number of tries for retryable failures. Zero means DefaultGHAttempts.
+	Attempts int
+	// Stdout, when set, receives gh's stdout instead of it being captured and returned.
+	Stdout io.Writer
+	// Stderr, when set, also receives gh's stderr as it is produced.
+	Stderr io.Writer
+	// BeforeRetry is called before each retry, e.g. to clear a partial download.
+	BeforeRetry func()
+}
+
+// GH runs gh with the default timeout and retry policy and returns its stdout.
+func GH(args ...string) ([]byte, error) {
+	return RunGH(GHOptions{}, args...)
+}
+
+// RunGH runs gh with a per-attempt timeout, retrying network failures with
+// exponential backoff. Auth and other failures are returned immediately.
+func RunGH(opts GHOptions, args ...string) ([]byte, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultGHTimeout
+	}
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = DefaultGHAttempts
+	}
+
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		output, err := runGHOnce(timeout, opts, args)
+		if err == nil {
+			return output, nil
+		}
+
+		var ghErr *GHError
+		if !errors.As(err, &ghErr) || ghErr.Kind != GHErrorNetwork || attempt >= attempts {
+			if ghErr != nil {
+				ghErr.Attempts = attempt
+			}
+			return nil, err
+		}
+
+		log.Warnf("gh %s failed (attempt %d/%d), retrying in %s: %s", strings.Join(ghSubcommand(args), " "), attempt, attempts, backoff, ghErr.Stderr)
+		ghSleep(backoff)
+		backoff *= 2
+		if opts.BeforeRetry != nil {
+			opts.BeforeRetry()
+		}
+	}
+}
+
+func runGHOnce(timeout time.Duration, opts GHOptions, args []string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Stdout = &stdout
+	if opts.Stdout != nil {
+		cmd.Stdout = opts.Stdout
+	}
+	cmd.Stderr = &stderr
+	if opts.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, opts.Stderr)
+	}
+
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+
+	stderrText := strings.TrimSpace(stderr.String())
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, &GHError{Args: args, Kind: GHErrorNetwork, Stderr: stderrText, Err: fmt.Errorf("timed out after %s", timeout)}
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		// gh could not be started at all (e.g. not installed)
+		return nil, &GHError{Args: args, Kind: GHErrorOther, Err: err}
+	}
+	return nil, &GHError{Args: args, Kind: ClassifyGHError(stderrText), Stderr: stderrText, Err: err}
+}
+
+// authMarkers and networkMarkers are lowercase substrings of gh stderr output.
+var (
+	authMarkers = []string{
+		"gh auth login",
+		"not logged in",
+		"authentication required",
+		"bad credentials",
+		"http 401",
+		"requires authentication",
+		"token has expired",
+		"saml enforcement",
+	}
+	networkMarkers = []string{
+		"timeout",
+		"timed out",
+		"connection reset",
+		"connection refused",
+		"no such host",
+		"could not resolve host",
+		"network is unreachable",
+		"tls handshake",
+		"unexpected eof",
+		"http 500",
+		"http 502",
+		"http 503",
+		"http 504",
+		"secondary rate limit",
+	}
+)
+
+// ClassifyGHError classifies gh stderr output as an auth, network, or other failure.
+func ClassifyGHError(stderr string) GHErrorKind {
+	lower := strings.ToLower(stderr)
+	for _, m := range authMarkers {
+		if strings.Contains(lower, m) {
+			return GHErrorAuth
+		}
+	}
+	for _, m := range networkMarkers {
+		if strings.Contains(lower, m) {
+			return GHErrorNetwork
+		}
+	}
+	return GHErrorOther
+}
+
+// ghSubcommand returns the leading non-flag arguments (e.g. "pr create") for log messages.
+func ghSubcommand(args []string) []string {
+	var sub []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") || len(sub) == 2 {
+			break
+		}
+		sub = append(sub, a)
+	}
+	return sub
+}
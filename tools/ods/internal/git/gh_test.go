@@ -0,0 +1,107 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClassifyGHError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   GHErrorKind
+	}{
+		{"To get started with GitHub CLI, please run:  gh auth login", GHErrorAuth},
+		{"HTTP 401: Bad credentials (https://api.github.com/graphql)", GHErrorAuth},
+		{"Post \"https://api.github.com/graphql\": dial tcp: lookup api.github.com: no such host", GHErrorNetwork},
+		{"HTTP 502: Bad Gateway", GHErrorNetwork},
+		{"read tcp 10.0.0.1:443: connection reset by peer", GHErrorNetwork},
+		{"no pull requests found for branch \"foo\"", GHErrorOther},
+		{"", GHErrorOther},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyGHError(tt.stderr); got != tt.want {
+			t.Errorf("ClassifyGHError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+// fakeGH puts a gh script on PATH that runs the given shell body.
+func fakeGH(t *testing.T, body string) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func noSleep(t *testing.T) {
+	t.Helper()
+	orig := ghSleep
+	ghSleep = func(time.Duration) {}
+	t.Cleanup(func() { ghSleep = orig })
+}
+
+func TestRunGH_RetriesNetworkErrors(t *testing.T) {
+	noSleep(t)
+	dir := fakeGH(t, `count_file="$(dirname "$0")/count"
+n=$(cat "$count_file" 2>/dev/null || echo 0)
+n=$((n+1))
+echo $n > "$count_file"
+if [ $n -lt 3 ]; then echo "HTTP 503: Service Unavailable" >&2; exit 1; fi
+echo ok`)
+
+	output, err := GH("pr", "view", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(output) != "ok\n" {
+		t.Errorf("output = %q, want %q", output, "ok\n")
+	}
+
+	count, _ := os.ReadFile(filepath.Join(dir, "count"))
+	if string(count) != "3\n" {
+		t.Errorf("gh invoked %q times, want 3", count)
+	}
+}
+
+func TestRunGH_DoesNotRetryAuthErrors(t *testing.T) {
+	noSleep(t)
+	dir := fakeGH(t, `echo x >> "$(dirname "$0")/calls"
+echo "You are not logged into any GitHub hosts. Run gh auth login to authenticate." >&2
+exit 4`)
+
+	_, err := GH("pr", "create")
+	var ghErr *GHError
+	if !errors.As(err, &ghErr) {
+		t.Fatalf("expected *GHError, got %v", err)
+	}
+	if ghErr.Kind != GHErrorAuth {
+		t.Errorf("Kind = %v, want auth", ghErr.Kind)
+	}
+
+	calls, _ := os.ReadFile(filepath.Join(dir, "calls"))
+	if string(calls) != "x\n" {
+		t.Errorf("gh invoked %d times, want 1", len(calls)/2)
+	}
+}
+
+func TestRunGH_Timeout(t *testing.T) {
+	noSleep(t)
+	fakeGH(t, `exec sleep 5`)
+
+	_, err := RunGH(GHOptions{Timeout: 100 * time.Millisecond, Attempts: 1}, "run", "list")
+	var ghErr *GHError
+	if !errors.As(err, &ghErr) {
+		t.Fatalf("expected *GHError, got %v", err)
+	}
+	if ghErr.Kind != GHErrorNetwork {
+		t.Errorf("Kind = %v, want network", ghErr.Kind)
+	}
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,6 +13,10 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// ErrNoCherryPickState is returned by LoadCherryPickState when no ods
+// cherry-pick has been started in this repository.
+var ErrNoCherryPickState = errors.New("no cherry-pick state")
+
 // CheckGitHubCLI checks if the GitHub CLI is installed and exits with a helpful message if not
 func CheckGitHubCLI() {
 	cmd := exec.Command("gh", "--version")
@@ -79,6 +84,15 @@ func BranchExists(branchName string) bool {
 	return cmd.Run() == nil
 }
 
+// IsValidBranchName checks if name is a legal git branch name
+func IsValidBranchName(name string) bool {
+	if name == "" {
+		return false
+	}
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	return cmd.Run() == nil
+}
+
 // HasUncommittedChanges checks if there are uncommitted changes in the working directory
 func HasUncommittedChanges() bool {
 	// git diff --quiet returns exit code 1 if there are changes
@@ -159,8 +173,14 @@ func FetchCommits(commitSHAs []string) error {
 
 // HasMergeConflict checks if the repository is in a merge conflict state
 func HasMergeConflict() bool {
+	return HasMergeConflictIn("")
+}
+
+// HasMergeConflictIn is HasMergeConflict for the worktree at dir ("" for the
+// current directory).
+func HasMergeConflictIn(dir string) bool {
 	// Check if there are unmerged files (indicates merge conflict)
-	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd := exec.Command("git", InDir(dir, "diff", "--name-only", "--diff-filter=U")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -170,10 +190,25 @@ func HasMergeConflict() bool {
 
 // IsCherryPickInProgress checks if a cherry-pick is currently in progress
 func IsCherryPickInProgress() bool {
-	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "CHERRY_PICK_HEAD")
+	return IsCherryPickInProgressIn("")
+}
+
+// IsCherryPickInProgressIn is IsCherryPickInProgress for the worktree at dir
+// ("" for the current directory).
+func IsCherryPickInProgressIn(dir string) bool {
+	cmd := exec.Command("git", InDir(dir, "rev-parse", "--verify", "--quiet", "CHERRY_PICK_HEAD")...)
 	return cmd.Run() == nil
 }
 
+// InDir prefixes git args with -C dir when dir is set, so the same command
+// runs in the current directory ("") or another worktree.
+func InDir(dir string, args ...string) []string {
+	if dir == "" {
+		return args
+	}
+	return append([]string{"-C", dir}, args...)
+}
+
 // CountUniqueCommits returns the number of commits on branch that are not on upstream.
 func CountUniqueCommits(branch, upstream string) (int, error) {
 	cmd := exec.Command("git", "rev-list", "--count", fmt.Sprintf("%s..%s", upstream, branch))
@@ -196,7 +231,13 @@ func IsRebaseInProgress() bool {
 
 // HasStagedChanges checks if there are staged changes in the index
 func HasStagedChanges() bool {
-	cmd := exec.Command("git", "diff", "--quiet", "--cached")
+	return HasStagedChangesIn("")
+}
+
+// HasStagedChangesIn is HasStagedChanges for the worktree at dir ("" for the
+// current directory).
+func HasStagedChangesIn(dir string) bool {
+	cmd := exec.Command("git", InDir(dir, "diff", "--quiet", "--cached")...)
 	return cmd.Run() != nil
 }
 
@@ -239,13 +280,9 @@ func IsCommitAppliedOnBranch(commitSHA, branchName string) bool {
 
 // ResolvePRToMergeCommit resolves a GitHub PR number to its merge commit SHA
 func ResolvePRToMergeCommit(prNumber string) (string, error) {
-	cmd := exec.Command("gh", "pr", "view", prNumber, "--json", "mergeCommit", "--jq", ".mergeCommit.oid")
-	output, err := cmd.Output()
+	output, err := GH("pr", "view", prNumber, "--json", "mergeCommit", "--jq", ".mergeCommit.oid")
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("gh pr view failed: %w: %s", err, string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("gh pr view failed: %w", err)
+		return "", err
 	}
 	sha := strings.TrimSpace(string(output))
 	if sha == "" || sha == "null" {
@@ -258,13 +295,9 @@ func ResolvePRToMergeCommit(prNumber string) (string, error) {
 // introduced it (best-effort; the GitHub API returns associated PRs for a
 // commit). Returns an error if no associated PR is found.
 func ResolveCommitToPR(commitSHA string) (string, error) {
-	cmd := exec.Command("gh", "api", fmt.Sprintf("repos/{owner}/{repo}/commits/%s/pulls", commitSHA), "--jq", ".[0].number")
-	output, err := cmd.Output()
+	output, err := GH("api", fmt.Sprintf("repos/{owner}/{repo}/commits/%s/pulls", commitSHA), "--jq", ".[0].number")
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("gh api commits/pulls failed: %w: %s", err, string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("gh api commits/pulls failed: %w", err)
+		return "", err
 	}
 	prNumber := strings.TrimSpace(string(output))
 	if prNumber == "" || prNumber == "null" {
@@ -273,6 +306,26 @@ func ResolveCommitToPR(commitSHA string) (string, error) {
 	return prNumber, nil
 }
 
+// PRInfo is the subset of `gh pr view` fields used to verify a created PR.
+type PRInfo struct {
+	URL         string `json:"url"`
+	State       string `json:"state"` // OPEN, CLOSED, or MERGED
+	BaseRefName string `json:"baseRefName"`
+}
+
+// GetPRInfo looks up a PR by number, URL, or branch.
+func GetPRInfo(pr string) (*PRInfo, error) {
+	output, err := GH("pr", "view", pr, "--json", "url,state,baseRefName")
+	if err != nil {
+		return nil, err
+	}
+	var info PRInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr view output: %w", err)
+	}
+	return &info, nil
+}
+
 // cherryPickWorkflowFile is the workflow file name dispatched by --dispatch.
 const cherryPickWorkflowFile = "post-merge-beta-cherry-pick.yml"
 
@@ -308,6 +361,14 @@ func DispatchCherryPickWorkflow(mergeCommitSHA, prNumber, release string, dryRun
 	return nil
 }
 
+// AbortCherryPick aborts an in-progress git cherry-pick, if any
+func AbortCherryPick() error {
+	if !IsCherryPickInProgress() {
+		return nil
+	}
+	return RunCommand("cherry-pick", "--abort")
+}
+
 // RunCherryPickContinue runs git cherry-pick --continue --no-edit
 func RunCherryPickContinue() error {
 	return RunCommandVerboseOnError("cherry-pick", "--continue", "--no-edit")
@@ -320,12 +381,26 @@ type CherryPickState struct {
 	CommitMessages    []string `json:"commit_messages"`
 	Releases          []string `json:"releases"`
 	Assignees         []string `json:"assignees,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
 	CompletedReleases []string `json:"completed_releases,omitempty"`
 	Stashed           bool     `json:"stashed"`
 	NoVerify          bool     `json:"no_verify"`
 	DryRun            bool     `json:"dry_run"`
 	BranchSuffix      string   `json:"branch_suffix"`
 	PRTitle           string   `json:"pr_title"`
+	PRTitleOverride   bool     `json:"pr_title_override,omitempty"`
+	KeepGoing         bool     `json:"keep_going,omitempty"`
+	FailedReleases    []string `json:"failed_releases,omitempty"`
+	// Onto is an arbitrary target branch that replaces the release branches.
+	// When set, Releases holds just this branch name.
+	Onto string `json:"onto,omitempty"`
+	// Cleanup deletes each local hotfix branch once its PR has been created.
+	Cleanup bool `json:"cleanup,omitempty"`
+	// Force cherry-picks commits even when they already appear to be on the
+	// target branch.
+	Force bool `json:"force,omitempty"`
+	// Open opens each created PR in the browser.
+	Open bool `json:"open,omitempty"`
 }
 
 const cherryPickStateFile = "ods-cherry-pick-state"
@@ -368,7 +443,7 @@ func LoadCherryPickState() (*CherryPickState, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("no cherry-pick state file found at %s — did you start a cherry-pick with ods?", path)
+			return nil, fmt.Errorf("no cherry-pick state file found at %s — did you start a cherry-pick with ods?: %w", path, ErrNoCherryPickState)
 		}
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -74,16 +75,17 @@ func TestCherryPickStateRoundTrip(t *testing.T) {
 	newTestRepo(t)
 
 	state := &CherryPickState{
-		OriginalBranch: "main",
-		CommitSHAs:     []string{"abc123", "def456"},
-		CommitMessages: []string{"fix: something", "feat: another"},
-		Releases:       []string{"v2.12"},
-		Assignees:      []string{"alice", "bob"},
-		Stashed:        true,
-		NoVerify:       false,
-		DryRun:         true,
-		BranchSuffix:   "abc123-def456",
-		PRTitle:        "chore(hotfix): cherry-pick 2 commits",
+		OriginalBranch:  "main",
+		CommitSHAs:      []string{"abc123", "def456"},
+		CommitMessages:  []string{"fix: something", "feat: another"},
+		Releases:        []string{"v2.12"},
+		Assignees:       []string{"alice", "bob"},
+		Stashed:         true,
+		NoVerify:        false,
+		DryRun:          true,
+		BranchSuffix:    "abc123-def456",
+		PRTitle:         "fix: login backport",
+		PRTitleOverride: true,
 	}
 
 	if err := SaveCherryPickState(state); err != nil {
@@ -110,6 +112,9 @@ func TestCherryPickStateRoundTrip(t *testing.T) {
 	if len(loaded.Assignees) != len(state.Assignees) {
 		t.Errorf("Assignees len = %d, want %d", len(loaded.Assignees), len(state.Assignees))
 	}
+	if loaded.PRTitle != state.PRTitle || loaded.PRTitleOverride != state.PRTitleOverride {
+		t.Errorf("PRTitle = %q (override %v), want %q (override %v)", loaded.PRTitle, loaded.PRTitleOverride, state.PRTitle, state.PRTitleOverride)
+	}
 
 	CleanCherryPickState()
 
@@ -121,8 +126,12 @@ func TestCherryPickStateRoundTrip(t *testing.T) {
 func TestLoadCherryPickStateMissing(t *testing.T) {
 	newTestRepo(t)
 
-	if _, err := LoadCherryPickState(); err == nil {
-		t.Error("expected error for missing state file")
+	_, err := LoadCherryPickState()
+	if err == nil {
+		t.Fatal("expected error for missing state file")
+	}
+	if !errors.Is(err, ErrNoCherryPickState) {
+		t.Errorf("expected ErrNoCherryPickState, got %v", err)
 	}
 }
 
@@ -182,3 +191,18 @@ func TestIsCommitAppliedOnBranch_NoFalsePositiveFromBody(t *testing.T) {
 		t.Error("should NOT match when subject only appears in body of another commit")
 	}
 }
+
+func TestIsValidBranchName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"hotfix/fix-login-v2.11": true,
+		"hotfix/abc12345-v1.0":   true,
+		"":                       false,
+		"hotfix/bad..name":       false,
+		"hotfix/with space":      false,
+		"hotfix/trailing.lock":   false,
+	} {
+		if got := IsValidBranchName(name); got != want {
+			t.Errorf("IsValidBranchName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// releaseBranchRe matches release branch names such as release/v2.5 or
+// release/v2.5-hotfix. Anything after major.minor is kept as the suffix.
+var releaseBranchRe = regexp.MustCompile(`^release/v(\d+)\.(\d+)(.*)$`)
+
+// ReleaseBranch is a remote release/v*.* branch.
+type ReleaseBranch struct {
+	Name   string // full branch name, e.g. "release/v2.5"
+	Major  int
+	Minor  int
+	Suffix string // anything after vMAJOR.MINOR, e.g. "-hotfix"; empty for plain release branches
+}
+
+// Version returns the branch's release version, e.g. "v2.5".
+func (b ReleaseBranch) Version() string {
+	return fmt.Sprintf("v%d.%d", b.Major, b.Minor)
+}
+
+// IsStable reports whether the branch is a plain release branch without a suffix.
+func (b ReleaseBranch) IsStable() bool {
+	return b.Suffix == ""
+}
+
+// ListRemoteReleaseBranches returns the release/v*.* branches on origin,
+// sorted by version (oldest first).
+func ListRemoteReleaseBranches() ([]ReleaseBranch, error) {
+	cmd := exec.Command("git", "ls-remote", "--heads", "origin", "refs/heads/release/v*")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote failed: %w", err)
+	}
+	return ParseReleaseBranches(string(output)), nil
+}
+
+// ParseReleaseBranches parses "git ls-remote --heads" output into release
+// branches sorted by version. Stable branches sort before suffixed branches of
+// the same version. Non-release refs are ignored.
+func ParseReleaseBranches(lsRemote string) []ReleaseBranch {
+	var branches []ReleaseBranch
+	for _, line := range strings.Split(lsRemote, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		name := strings.TrimPrefix(fields[1], "refs/heads/")
+		m := releaseBranchRe.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		major, _ := strconv.Atoi(m[1])
+		minor, _ := strconv.Atoi(m[2])
+		branches = append(branches, ReleaseBranch{Name: name, Major: major, Minor: minor, Suffix: m[3]})
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		a, b := branches[i], branches[j]
+		if a.Major != b.Major {
+			return a.Major < b.Major
+		}
+		if a.Minor != b.Minor {
+			return a.Minor < b.Minor
+		}
+		if a.IsStable() != b.IsStable() {
+			return a.IsStable()
+		}
+		return a.Suffix < b.Suffix
+	})
+	return branches
+}
+
+// LatestStableRelease returns the highest-versioned stable branch, if any.
+func LatestStableRelease(branches []ReleaseBranch) (ReleaseBranch, bool) {
+	for i := len(branches) - 1; i >= 0; i-- {
+		if branches[i].IsStable() {
+			return branches[i], true
+		}
+	}
+	return ReleaseBranch{}, false
+}
@@ -0,0 +1,42 @@
+package git
+
+import "testing"
+
+func TestParseReleaseBranches(t *testing.T) {
+	output := `aaa	refs/heads/release/v2.10
+bbb	refs/heads/release/v2.9
+ccc	refs/heads/release/v2.10-hotfix
+ddd	refs/heads/release/v1.0
+eee	refs/heads/release/next
+fff	refs/heads/release/v3.0-beta
+`
+	branches := ParseReleaseBranches(output)
+
+	want := []string{
+		"release/v1.0",
+		"release/v2.9",
+		"release/v2.10",
+		"release/v2.10-hotfix",
+		"release/v3.0-beta",
+	}
+	if len(branches) != len(want) {
+		t.Fatalf("got %d branches, want %d: %+v", len(branches), len(want), branches)
+	}
+	for i, name := range want {
+		if branches[i].Name != name {
+			t.Errorf("branches[%d] = %q, want %q", i, branches[i].Name, name)
+		}
+	}
+
+	if branches[3].IsStable() || branches[3].Suffix != "-hotfix" {
+		t.Errorf("expected release/v2.10-hotfix to be suffixed, got %+v", branches[3])
+	}
+	if branches[2].Version() != "v2.10" {
+		t.Errorf("Version() = %q, want v2.10", branches[2].Version())
+	}
+
+	latest, ok := LatestStableRelease(branches)
+	if !ok || latest.Name != "release/v2.10" {
+		t.Errorf("LatestStableRelease = %+v, %v; want release/v2.10", latest, ok)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	_ "image/gif"  // register GIF decoder for decodeImage
+	_ "image/jpeg" // register JPEG decoder for decodeImage
 	"image/png"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	_ "golang.org/x/image/webp" // register WebP decoder for decodeImage
 )
 
 // Status represents the comparison status of a screenshot.
@@ -24,6 +28,9 @@ const (
 	StatusAdded
 	// StatusRemoved means the image exists only in the baseline directory (no current).
 	StatusRemoved
+	// StatusError means the pair exists but one of the images could not be read
+	// or decoded, so it was not compared. Result.Error holds the reason.
+	StatusError
 )
 
 // String returns a human-readable string for the status.
@@ -37,52 +44,145 @@ func (s Status) String() string {
 		return "added"
 	case StatusRemoved:
 		return "removed"
+	case StatusError:
+		return "error"
 	default:
 		return "unknown"
 	}
 }
 
+// MarshalText encodes the status as its String form, so JSON output carries
+// "changed" rather than a number.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // Result holds the comparison result for a single screenshot.
 type Result struct {
 	// Name is the filename of the screenshot (e.g. "admin-documents-explorer.png").
-	Name string
+	Name string `json:"name"`
 
 	// Status is the comparison status.
-	Status Status
+	Status Status `json:"status"`
 
 	// DiffPercent is the percentage of pixels that differ (0.0 to 100.0).
-	DiffPercent float64
+	DiffPercent float64 `json:"diff_percent"`
 
 	// DiffPixels is the number of pixels that differ.
-	DiffPixels int
+	DiffPixels int `json:"diff_pixels"`
 
 	// TotalPixels is the total number of pixels compared.
-	TotalPixels int
+	TotalPixels int `json:"total_pixels"`
 
 	// BaselinePath is the path to the baseline image (empty if added).
-	BaselinePath string
+	BaselinePath string `json:"baseline_path,omitempty"`
 
 	// CurrentPath is the path to the current image (empty if removed).
-	CurrentPath string
+	CurrentPath string `json:"current_path,omitempty"`
 
 	// DiffImage is the generated diff overlay image (nil if unchanged, added, or removed).
-	DiffImage image.Image
+	DiffImage image.Image `json:"-"`
+
+	// DiffPath is where SaveDiffImages wrote DiffImage (empty if not saved).
+	DiffPath string `json:"diff_path,omitempty"`
+
+	// Error is why the pair could not be compared (only set for StatusError).
+	Error string `json:"error,omitempty"`
+
+	// Masked lists the regions excluded from the comparison.
+	Masked []Rect `json:"masked,omitempty"`
+
+	// SSIM is the structural similarity score (0.0 to 1.0) when compared in
+	// ModeSSIM; zero otherwise.
+	SSIM float64 `json:"ssim,omitempty"`
+
+	// BaselineSize and CurrentSize are the original image dimensions of a
+	// compared pair (zero for added, removed, and error results).
+	BaselineSize Size `json:"baseline_size,omitzero"`
+	CurrentSize  Size `json:"current_size,omitzero"`
+
+	// Resized is set when one image was scaled to the other's dimensions
+	// before comparing (see CompareOptions.Resize).
+	Resized bool `json:"resized,omitempty"`
 }
 
-// Compare compares two PNG images pixel-by-pixel and returns the result.
-// The threshold parameter (0.0 to 1.0) controls per-channel sensitivity:
-// a pixel is considered different if any channel differs by more than threshold * 255.
-func Compare(baselinePath, currentPath string, threshold float64) (*Result, error) {
-	baseline, err := decodePNG(baselinePath)
+// Size is the width and height of an image in pixels.
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// String returns the size as "WIDTHxHEIGHT".
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// DimensionsChanged reports whether a compared pair's images differ in size.
+func (r Result) DimensionsChanged() bool {
+	return r.BaselineSize != r.CurrentSize
+}
+
+// CompareOptions controls how image pairs are compared.
+type CompareOptions struct {
+	// Threshold (0.0 to 1.0) controls per-channel sensitivity: a pixel is
+	// considered different if any channel differs by more than threshold * 255.
+	Threshold float64
+
+	// Masks excludes regions of matching screenshots from the comparison.
+	Masks Masks
+
+	// MinDiffPercent (0.0 to 100.0) reports pairs whose DiffPercent is below
+	// it as unchanged, so a handful of anti-aliased pixels don't count as a
+	// change. Zero means any differing pixel is a change.
+	MinDiffPercent float64
+
+	// Mode selects exact pixel comparison (the default) or SSIM.
+	Mode Mode
+
+	// SSIMThreshold is the score below which a pair is changed in ModeSSIM.
+	// Zero means DefaultSSIMThreshold.
+	SSIMThreshold float64
+
+	// Resize scales the smaller image of a mismatched pair up to the larger
+	// one's dimensions before comparing. By default the smaller image is
+	// padded with transparent pixels, so the extra area all counts as changed.
+	Resize bool
+
+	// Filter, when set, is a glob (filepath.Match syntax) that
+	// CompareDirectories matches against screenshot names; other screenshots
+	// are skipped entirely and left out of the results.
+	Filter string
+}
+
+// imageExtensions are the screenshot file extensions CompareDirectories
+// picks up. Each needs a decoder registered with the image package.
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
+
+// Compare compares two images pixel-by-pixel and returns the result. Pixels
+// in masked regions are neither compared nor counted in TotalPixels.
+func Compare(baselinePath, currentPath string, opts CompareOptions) (*Result, error) {
+	baseline, err := decodeImage(baselinePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode baseline %s: %w", baselinePath, err)
 	}
 
-	current, err := decodePNG(currentPath)
+	current, err := decodeImage(currentPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode current %s: %w", currentPath, err)
 	}
 
+	baselineSize := Size{Width: baseline.Bounds().Dx(), Height: baseline.Bounds().Dy()}
+	currentSize := Size{Width: current.Bounds().Dx(), Height: current.Bounds().Dy()}
+	resized := false
+	if opts.Resize && baselineSize != currentSize {
+		if baselineSize.Width*baselineSize.Height < currentSize.Width*currentSize.Height {
+			baseline = scaleImage(baseline, currentSize)
+		} else {
+			current = scaleImage(current, baselineSize)
+		}
+		resized = true
+	}
+
 	baselineBounds := baseline.Bounds()
 	currentBounds := current.Bounds()
 
@@ -90,6 +190,7 @@ func Compare(baselinePath, currentPath string, threshold float64) (*Result, erro
 	width := max(baselineBounds.Dx(), currentBounds.Dx())
 	height := max(baselineBounds.Dy(), currentBounds.Dy())
 	totalPixels := width * height
+	masked := opts.Masks.For(filepath.Base(currentPath))
 
 	if totalPixels == 0 {
 		return &Result{
@@ -97,15 +198,38 @@ func Compare(baselinePath, currentPath string, threshold float64) (*Result, erro
 			Status:       StatusUnchanged,
 			BaselinePath: baselinePath,
 			CurrentPath:  currentPath,
+			Masked:       masked,
+			BaselineSize: baselineSize,
+			CurrentSize:  currentSize,
 		}, nil
 	}
 
 	diffImage := image.NewRGBA(image.Rect(0, 0, width, height))
 	diffPixels := 0
-	thresholdValue := threshold * 255.0
+	thresholdValue := opts.Threshold * 255.0
+
+	// Luminance planes for SSIM. Masked pixels use the baseline value on both
+	// sides so they never lower the score.
+	var baselineLuma, currentLuma []float64
+	if opts.Mode == ModeSSIM {
+		baselineLuma = make([]float64, width*height)
+		currentLuma = make([]float64, width*height)
+	}
 
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
+			if inMask(masked, x, y) {
+				if baselineLuma != nil && x < baselineBounds.Dx() && y < baselineBounds.Dy() {
+					br, bg, bb, _ := baseline.At(baselineBounds.Min.X+x, baselineBounds.Min.Y+y).RGBA()
+					l := luminance(float64(br>>8), float64(bg>>8), float64(bb>>8))
+					baselineLuma[y*width+x], currentLuma[y*width+x] = l, l
+				}
+				totalPixels--
+				// Show masked regions as flat gray in the diff overlay
+				diffImage.Set(x, y, color.RGBA{R: 128, G: 128, B: 128, A: 255})
+				continue
+			}
+
 			// Get pixel from each image (transparent if out of bounds)
 			var br, bg, bb, ba uint32
 			var cr, cg, cb, ca uint32
@@ -127,6 +251,11 @@ func Compare(baselinePath, currentPath string, threshold float64) (*Result, erro
 			cb8 := float64(cb >> 8)
 			ca8 := float64(ca >> 8)
 
+			if baselineLuma != nil {
+				baselineLuma[y*width+x] = luminance(br8, bg8, bb8)
+				currentLuma[y*width+x] = luminance(cr8, cg8, cb8)
+			}
+
 			// Check if channels differ beyond threshold
 			isDiff := math.Abs(br8-cr8) > thresholdValue ||
 				math.Abs(bg8-cg8) > thresholdValue ||
@@ -149,35 +278,85 @@ func Compare(baselinePath, currentPath string, threshold float64) (*Result, erro
 		}
 	}
 
-	diffPercent := float64(diffPixels) / float64(totalPixels) * 100.0
-
-	status := StatusUnchanged
-	if diffPixels > 0 {
-		status = StatusChanged
+	var diffPercent float64
+	if totalPixels > 0 {
+		diffPercent = float64(diffPixels) / float64(totalPixels) * 100.0
 	}
 
-	return &Result{
+	result := &Result{
 		Name:         filepath.Base(currentPath),
-		Status:       status,
+		Status:       StatusUnchanged,
 		DiffPercent:  diffPercent,
 		DiffPixels:   diffPixels,
 		TotalPixels:  totalPixels,
 		BaselinePath: baselinePath,
 		CurrentPath:  currentPath,
-		DiffImage:    diffImage,
-	}, nil
+		Masked:       masked,
+		BaselineSize: baselineSize,
+		CurrentSize:  currentSize,
+		Resized:      resized,
+	}
+	changed := diffPixels > 0 && diffPercent >= opts.MinDiffPercent
+	if opts.Mode == ModeSSIM {
+		ssimThreshold := opts.SSIMThreshold
+		if ssimThreshold == 0 {
+			ssimThreshold = DefaultSSIMThreshold
+		}
+		result.SSIM = ssim(baselineLuma, currentLuma, width, height)
+		changed = diffPixels > 0 && result.SSIM < ssimThreshold
+	}
+	if changed {
+		result.Status = StatusChanged
+		result.DiffImage = diffImage
+	}
+	return result, nil
 }
 
-// CompareDirectories compares all PNG files in two directories.
+// scaleImage resizes img to size using nearest-neighbor sampling, which
+// keeps edges crisp rather than inventing blended pixels.
+func scaleImage(img image.Image, size Size) image.Image {
+	bounds := img.Bounds()
+	scaled := image.NewRGBA(image.Rect(0, 0, size.Width, size.Height))
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return scaled
+	}
+	for y := 0; y < size.Height; y++ {
+		srcY := bounds.Min.Y + y*bounds.Dy()/size.Height
+		for x := 0; x < size.Width; x++ {
+			srcX := bounds.Min.X + x*bounds.Dx()/size.Width
+			scaled.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+	return scaled
+}
+
+// inMask reports whether the pixel (x, y) is inside any of the regions.
+func inMask(regions []Rect, x, y int) bool {
+	for _, r := range regions {
+		if r.contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareDirectories compares all supported image files (see imageExtensions)
+// in two directories, or only those matching opts.Filter.
 // Files are matched by name. Files only in baseline are "removed",
 // files only in current are "added", and matching files are compared.
-func CompareDirectories(baselineDir, currentDir string, threshold float64) ([]Result, error) {
-	baselineFiles, err := listPNGs(baselineDir)
+func CompareDirectories(baselineDir, currentDir string, opts CompareOptions) ([]Result, error) {
+	if opts.Filter != "" {
+		if _, err := filepath.Match(opts.Filter, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", opts.Filter, err)
+		}
+	}
+
+	baselineFiles, err := listImages(baselineDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list baseline directory: %w", err)
 	}
 
-	currentFiles, err := listPNGs(currentDir)
+	currentFiles, err := listImages(currentDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list current directory: %w", err)
 	}
@@ -205,14 +384,28 @@ func CompareDirectories(baselineDir, currentDir string, threshold float64) ([]Re
 	var results []Result
 
 	for name := range allNames {
+		if opts.Filter != "" {
+			if ok, _ := filepath.Match(opts.Filter, name); !ok {
+				continue
+			}
+		}
+
 		baselinePath, inBaseline := baselineMap[name]
 		currentPath, inCurrent := currentMap[name]
 
 		switch {
 		case inBaseline && inCurrent:
-			result, err := Compare(baselinePath, currentPath, threshold)
+			result, err := Compare(baselinePath, currentPath, opts)
 			if err != nil {
-				return nil, fmt.Errorf("failed to compare %s: %w", name, err)
+				// Report unreadable pairs instead of aborting the whole run
+				results = append(results, Result{
+					Name:         name,
+					Status:       StatusError,
+					BaselinePath: baselinePath,
+					CurrentPath:  currentPath,
+					Error:        err.Error(),
+				})
+				continue
 			}
 			results = append(results, *result)
 
@@ -232,7 +425,7 @@ func CompareDirectories(baselineDir, currentDir string, threshold float64) ([]Re
 		}
 	}
 
-	// Sort: changed first (by diff % descending), then added, removed, unchanged
+	// Sort: errors first, then changed (by diff % descending), added, removed, unchanged
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Status != results[j].Status {
 			return statusOrder(results[i].Status) < statusOrder(results[j].Status)
@@ -265,15 +458,34 @@ func SaveDiffImage(img image.Image, path string) error {
 	return nil
 }
 
-// decodePNG reads and decodes a PNG file.
-func decodePNG(path string) (image.Image, error) {
+// SaveDiffImages writes the diff overlay of each changed result to dir as
+// <name>.diff.png and records the path in its DiffPath. The name keeps its
+// extension so a.png and a.jpg don't overwrite each other's diff.
+func SaveDiffImages(results []Result, dir string) error {
+	for i := range results {
+		r := &results[i]
+		if r.Status != StatusChanged || r.DiffImage == nil {
+			continue
+		}
+		path := filepath.Join(dir, r.Name+".diff.png")
+		if err := SaveDiffImage(r.DiffImage, path); err != nil {
+			return fmt.Errorf("failed to save diff for %s: %w", r.Name, err)
+		}
+		r.DiffPath = path
+	}
+	return nil
+}
+
+// decodeImage reads and decodes an image file, sniffing its format from the
+// content rather than trusting the extension.
+func decodeImage(path string) (image.Image, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = f.Close() }()
 
-	img, err := png.Decode(f)
+	img, _, err := image.Decode(f)
 	if err != nil {
 		return nil, err
 	}
@@ -281,8 +493,19 @@ func decodePNG(path string) (image.Image, error) {
 	return img, nil
 }
 
-// listPNGs returns all .png files in a directory (non-recursive).
-func listPNGs(dir string) ([]string, error) {
+// isImageFile reports whether name has a supported image extension.
+func isImageFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range imageExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// listImages returns all supported image files in a directory (non-recursive).
+func listImages(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -291,31 +514,33 @@ func listPNGs(dir string) ([]string, error) {
 		return nil, err
 	}
 
-	var pngs []string
+	var images []string
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
-		if strings.HasSuffix(strings.ToLower(entry.Name()), ".png") {
-			pngs = append(pngs, filepath.Join(dir, entry.Name()))
+		if isImageFile(entry.Name()) {
+			images = append(images, filepath.Join(dir, entry.Name()))
 		}
 	}
 
-	return pngs, nil
+	return images, nil
 }
 
 // statusOrder returns a sort priority for each status.
 func statusOrder(s Status) int {
 	switch s {
-	case StatusChanged:
+	case StatusError:
 		return 0
-	case StatusAdded:
+	case StatusChanged:
 		return 1
-	case StatusRemoved:
+	case StatusAdded:
 		return 2
-	case StatusUnchanged:
+	case StatusRemoved:
 		return 3
-	default:
+	case StatusUnchanged:
 		return 4
+	default:
+		return 5
 	}
 }
@@ -1,11 +1,16 @@
 package imgdiff
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"image"
 	"image/color"
+	"image/jpeg"
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -66,7 +71,7 @@ func TestCompare_IdenticalImages(t *testing.T) {
 	createTestPNG(t, baselinePath, 100, 100, white)
 	createTestPNG(t, currentPath, 100, 100, white)
 
-	result, err := Compare(baselinePath, currentPath, 0.2)
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("Compare failed: %v", err)
 	}
@@ -83,6 +88,9 @@ func TestCompare_IdenticalImages(t *testing.T) {
 	if result.TotalPixels != 10000 {
 		t.Errorf("expected 10000 total pixels, got %d", result.TotalPixels)
 	}
+	if result.DiffImage != nil {
+		t.Error("expected no DiffImage for an unchanged pair")
+	}
 }
 
 func TestCompare_DifferentImages(t *testing.T) {
@@ -98,7 +106,7 @@ func TestCompare_DifferentImages(t *testing.T) {
 	// Current: white with a 10x10 red block (100 pixels different)
 	createTestPNGWithBlock(t, currentPath, 100, 100, white, red, 0, 0, 10, 10)
 
-	result, err := Compare(baselinePath, currentPath, 0.2)
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("Compare failed: %v", err)
 	}
@@ -117,6 +125,81 @@ func TestCompare_DifferentImages(t *testing.T) {
 	}
 }
 
+func TestCompare_MinDiffPercent(t *testing.T) {
+	dir := t.TempDir()
+	baselinePath := filepath.Join(dir, "baseline.png")
+	currentPath := filepath.Join(dir, "current.png")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+	createTestPNG(t, baselinePath, 100, 100, white)
+	// 100 of 10000 pixels differ: 1%
+	createTestPNGWithBlock(t, currentPath, 100, 100, white, red, 0, 0, 10, 10)
+
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2, MinDiffPercent: 2})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Status != StatusUnchanged {
+		t.Errorf("expected StatusUnchanged below --min-diff-percent, got %s", result.Status)
+	}
+	if result.DiffPixels != 100 {
+		t.Errorf("expected the 100 diff pixels to still be counted, got %d", result.DiffPixels)
+	}
+
+	result, err = Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2, MinDiffPercent: 1})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Status != StatusChanged {
+		t.Errorf("expected StatusChanged at --min-diff-percent, got %s", result.Status)
+	}
+}
+
+func TestCompare_SSIM(t *testing.T) {
+	dir := t.TempDir()
+	baselinePath := filepath.Join(dir, "baseline.png")
+	speckPath := filepath.Join(dir, "speck.png")
+	blockPath := filepath.Join(dir, "block.png")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	gray := color.RGBA{R: 190, G: 190, B: 190, A: 255}
+	black := color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	createTestPNG(t, baselinePath, 100, 100, white)
+	// A single off pixel, like anti-aliasing noise
+	createTestPNGWithBlock(t, speckPath, 100, 100, white, gray, 50, 50, 1, 1)
+	// A large block, like a moved element
+	createTestPNGWithBlock(t, blockPath, 100, 100, white, black, 0, 0, 40, 40)
+
+	opts := CompareOptions{Threshold: 0.2, Mode: ModeSSIM}
+
+	result, err := Compare(baselinePath, speckPath, opts)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.DiffPixels != 1 {
+		t.Errorf("expected 1 diff pixel, got %d", result.DiffPixels)
+	}
+	if result.Status != StatusUnchanged {
+		t.Errorf("expected a single-pixel speck to be unchanged in ssim mode (ssim %.4f), got %s", result.SSIM, result.Status)
+	}
+
+	result, err = Compare(baselinePath, blockPath, opts)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Status != StatusChanged {
+		t.Errorf("expected a large block to be changed in ssim mode (ssim %.4f), got %s", result.SSIM, result.Status)
+	}
+	if result.SSIM <= 0 || result.SSIM >= DefaultSSIMThreshold {
+		t.Errorf("expected ssim in (0, %.2f), got %.4f", DefaultSSIMThreshold, result.SSIM)
+	}
+
+	if _, err := ParseMode("fuzzy"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
 func TestCompare_SubtleDifferenceBelowThreshold(t *testing.T) {
 	dir := t.TempDir()
 	baselinePath := filepath.Join(dir, "baseline.png")
@@ -130,7 +213,7 @@ func TestCompare_SubtleDifferenceBelowThreshold(t *testing.T) {
 	createTestPNG(t, currentPath, 10, 10, c2)
 
 	// Threshold 0.2 = 51 pixel value difference. 10 < 51, so should be unchanged.
-	result, err := Compare(baselinePath, currentPath, 0.2)
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("Compare failed: %v", err)
 	}
@@ -152,7 +235,7 @@ func TestCompare_DifferentSizes(t *testing.T) {
 	createTestPNG(t, baselinePath, 100, 100, white)
 	createTestPNG(t, currentPath, 100, 120, white) // Taller
 
-	result, err := Compare(baselinePath, currentPath, 0.2)
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("Compare failed: %v", err)
 	}
@@ -166,6 +249,33 @@ func TestCompare_DifferentSizes(t *testing.T) {
 	}
 }
 
+func TestCompare_Resize(t *testing.T) {
+	dir := t.TempDir()
+	baselinePath := filepath.Join(dir, "baseline.png")
+	currentPath := filepath.Join(dir, "current.png")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	createTestPNG(t, baselinePath, 100, 100, white)
+	createTestPNG(t, currentPath, 100, 120, white)
+
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2, Resize: true})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Status != StatusUnchanged {
+		t.Errorf("expected a scaled solid image to be unchanged, got %s (%.2f%%)", result.Status, result.DiffPercent)
+	}
+	if !result.Resized {
+		t.Error("expected Resized to be set")
+	}
+	if !result.DimensionsChanged() {
+		t.Error("expected DimensionsChanged for mismatched sizes")
+	}
+	if got := result.BaselineSize.String() + " → " + result.CurrentSize.String(); got != "100x100 → 100x120" {
+		t.Errorf("unexpected dimensions %q", got)
+	}
+}
+
 func TestCompareDirectories(t *testing.T) {
 	baselineDir := filepath.Join(t.TempDir(), "baseline")
 	currentDir := filepath.Join(t.TempDir(), "current")
@@ -188,7 +298,7 @@ func TestCompareDirectories(t *testing.T) {
 	// added.png: only in current
 	createTestPNG(t, filepath.Join(currentDir, "added.png"), 10, 10, blue)
 
-	results, err := CompareDirectories(baselineDir, currentDir, 0.2)
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("CompareDirectories failed: %v", err)
 	}
@@ -222,6 +332,148 @@ func TestCompareDirectories(t *testing.T) {
 	}
 }
 
+func TestSaveDiffImages(t *testing.T) {
+	baselineDir := filepath.Join(t.TempDir(), "baseline")
+	currentDir := filepath.Join(t.TempDir(), "current")
+	diffDir := filepath.Join(t.TempDir(), "diffs")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+	createTestPNG(t, filepath.Join(baselineDir, "same.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(currentDir, "same.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(baselineDir, "changed.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(currentDir, "changed.png"), 10, 10, red)
+	createTestPNG(t, filepath.Join(currentDir, "added.png"), 10, 10, red)
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if err := SaveDiffImages(results, diffDir); err != nil {
+		t.Fatalf("SaveDiffImages failed: %v", err)
+	}
+
+	for _, r := range results {
+		if r.Status != StatusChanged {
+			if r.DiffPath != "" {
+				t.Errorf("%s (%s): expected no diff path, got %q", r.Name, r.Status, r.DiffPath)
+			}
+			continue
+		}
+		want := filepath.Join(diffDir, "changed.png.diff.png")
+		if r.DiffPath != want {
+			t.Errorf("DiffPath = %q, want %q", r.DiffPath, want)
+		}
+		if _, err := os.Stat(want); err != nil {
+			t.Errorf("expected diff image on disk: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(diffDir)
+	if err != nil {
+		t.Fatalf("failed to read diff dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected exactly 1 diff image, got %d", len(entries))
+	}
+}
+
+func TestSaveDiffImages_KeepsExtension(t *testing.T) {
+	diffDir := filepath.Join(t.TempDir(), "diffs")
+	overlay := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	results := []Result{
+		{Name: "a.png", Status: StatusChanged, DiffImage: overlay},
+		{Name: "a.jpg", Status: StatusChanged, DiffImage: overlay},
+	}
+
+	if err := SaveDiffImages(results, diffDir); err != nil {
+		t.Fatalf("SaveDiffImages failed: %v", err)
+	}
+	for i, want := range []string{"a.png.diff.png", "a.jpg.diff.png"} {
+		if got := filepath.Base(results[i].DiffPath); got != want {
+			t.Errorf("%s: DiffPath = %q, want %s", results[i].Name, got, want)
+		}
+	}
+	entries, err := os.ReadDir(diffDir)
+	if err != nil {
+		t.Fatalf("failed to read diff dir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected 2 diff images, got %d", len(entries))
+	}
+}
+
+func TestCompareDirectories_Cases(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+	type fixture struct {
+		width, height int
+		block         bool // paint a 5x2 red block in the top-left corner
+	}
+
+	tests := []struct {
+		name        string
+		baseline    *fixture
+		current     *fixture
+		wantStatus  Status
+		wantPercent float64
+	}{
+		{"identical", &fixture{10, 10, false}, &fixture{10, 10, false}, StatusUnchanged, 0},
+		{"changed", &fixture{10, 10, false}, &fixture{10, 10, true}, StatusChanged, 10},
+		{"added", nil, &fixture{10, 10, false}, StatusAdded, 0},
+		{"removed", &fixture{10, 10, false}, nil, StatusRemoved, 0},
+		// The extra 10x10 rows are compared against transparent padding
+		{"size mismatch", &fixture{10, 10, false}, &fixture{10, 20, false}, StatusChanged, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baselineDir := filepath.Join(t.TempDir(), "baseline")
+			currentDir := filepath.Join(t.TempDir(), "current")
+			for _, dir := range []string{baselineDir, currentDir} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			write := func(dir string, img *fixture) {
+				if img == nil {
+					return
+				}
+				path := filepath.Join(dir, "page.png")
+				if img.block {
+					createTestPNGWithBlock(t, path, img.width, img.height, white, red, 0, 0, 5, 2)
+				} else {
+					createTestPNG(t, path, img.width, img.height, white)
+				}
+			}
+			write(baselineDir, tt.baseline)
+			write(currentDir, tt.current)
+
+			results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+			if err != nil {
+				t.Fatalf("CompareDirectories failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+
+			r := results[0]
+			if r.Name != "page.png" {
+				t.Errorf("expected name page.png, got %s", r.Name)
+			}
+			if r.Status != tt.wantStatus {
+				t.Errorf("expected %s, got %s", tt.wantStatus, r.Status)
+			}
+			if r.DiffPercent != tt.wantPercent {
+				t.Errorf("expected %.2f%% diff, got %.2f%%", tt.wantPercent, r.DiffPercent)
+			}
+		})
+	}
+}
+
 func TestCompareDirectories_EmptyBaseline(t *testing.T) {
 	baselineDir := filepath.Join(t.TempDir(), "baseline")
 	currentDir := filepath.Join(t.TempDir(), "current")
@@ -233,7 +485,7 @@ func TestCompareDirectories_EmptyBaseline(t *testing.T) {
 	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
 	createTestPNG(t, filepath.Join(currentDir, "new.png"), 10, 10, white)
 
-	results, err := CompareDirectories(baselineDir, currentDir, 0.2)
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("CompareDirectories failed: %v", err)
 	}
@@ -246,6 +498,152 @@ func TestCompareDirectories_EmptyBaseline(t *testing.T) {
 	}
 }
 
+func TestCompareDirectories_Filter(t *testing.T) {
+	baselineDir := filepath.Join(t.TempDir(), "baseline")
+	currentDir := filepath.Join(t.TempDir(), "current")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+	createTestPNG(t, filepath.Join(baselineDir, "admin-connectors-list.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(currentDir, "admin-connectors-list.png"), 10, 10, red)
+	createTestPNG(t, filepath.Join(currentDir, "admin-connectors-new.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(baselineDir, "chat-home.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(currentDir, "chat-home.png"), 10, 10, red)
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2, Filter: "admin-connectors-*"})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Name == "chat-home.png" {
+			t.Errorf("expected %s to be filtered out", r.Name)
+		}
+	}
+
+	if _, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Filter: "["}); err == nil {
+		t.Error("expected an error for a malformed filter")
+	}
+}
+
+func TestCompareDirectories_JPEG(t *testing.T) {
+	baselineDir := filepath.Join(t.TempDir(), "baseline")
+	currentDir := filepath.Join(t.TempDir(), "current")
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	for _, dir := range []string{baselineDir, currentDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(filepath.Join(dir, "shot.jpg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := jpeg.Encode(f, img, nil); err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+	}
+	// Non-image files are ignored
+	if err := os.WriteFile(filepath.Join(currentDir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Name != "shot.jpg" || results[0].Status != StatusUnchanged {
+		t.Errorf("expected unchanged shot.jpg, got %s %s", results[0].Name, results[0].Status)
+	}
+}
+
+func TestCompareDirectories_WebP(t *testing.T) {
+	baselineDir := filepath.Join(t.TempDir(), "baseline")
+	currentDir := filepath.Join(t.TempDir(), "current")
+
+	// 1x1 WebPs: a transparent lossless pixel and an opaque gray lossy one
+	lossless, _ := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	lossy, _ := base64.StdEncoding.DecodeString("UklGRiIAAABXRUJQVlA4IBYAAAAwAQCdASoBAAEADsD+JaQAA3AAAAAA")
+	files := map[string][]byte{
+		filepath.Join(baselineDir, "same.webp"):    lossy,
+		filepath.Join(currentDir, "same.webp"):     lossy,
+		filepath.Join(baselineDir, "changed.webp"): lossless,
+		filepath.Join(currentDir, "changed.webp"):  lossy,
+	}
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	want := map[string]Status{"same.webp": StatusUnchanged, "changed.webp": StatusChanged}
+	for _, r := range results {
+		if r.Status != want[r.Name] {
+			t.Errorf("%s: expected %s, got %s (%s)", r.Name, want[r.Name], r.Status, r.Error)
+		}
+	}
+}
+
+func TestCompareDirectories_DecodeError(t *testing.T) {
+	baselineDir := filepath.Join(t.TempDir(), "baseline")
+	currentDir := filepath.Join(t.TempDir(), "current")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	createTestPNG(t, filepath.Join(baselineDir, "ok.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(currentDir, "ok.png"), 10, 10, white)
+	createTestPNG(t, filepath.Join(baselineDir, "broken.png"), 10, 10, white)
+	// A truncated upload: PNG signature only
+	if err := os.WriteFile(filepath.Join(currentDir, "broken.png"), []byte("\x89PNG\r\n\x1a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Name != "broken.png" || results[0].Status != StatusError || results[0].Error == "" {
+		t.Errorf("expected broken.png first with an error, got %+v", results[0])
+	}
+	if results[1].Status != StatusUnchanged {
+		t.Errorf("expected ok.png unchanged, got %s", results[1].Status)
+	}
+
+	s := BuildSummary("admin", results)
+	if s.Errors != 1 || s.Changed != 0 || s.Failures != 1 || !s.HasDifferences {
+		t.Errorf("expected 1 error counted as a failure, got %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, results, ReportOptions{}); err != nil {
+		t.Fatalf("RenderReport failed: %v", err)
+	}
+	if !contains(buf.String(), "badge-error") {
+		t.Error("rendered report missing error badge")
+	}
+}
+
 func TestGenerateReport(t *testing.T) {
 	dir := t.TempDir()
 	baselineDir := filepath.Join(dir, "baseline")
@@ -257,13 +655,13 @@ func TestGenerateReport(t *testing.T) {
 	createTestPNG(t, filepath.Join(baselineDir, "page.png"), 50, 50, white)
 	createTestPNG(t, filepath.Join(currentDir, "page.png"), 50, 50, red)
 
-	results, err := CompareDirectories(baselineDir, currentDir, 0.2)
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
 	if err != nil {
 		t.Fatalf("CompareDirectories failed: %v", err)
 	}
 
 	outputPath := filepath.Join(dir, "report", "index.html")
-	if err := GenerateReport(results, outputPath); err != nil {
+	if err := GenerateReport(results, outputPath, ReportOptions{}); err != nil {
 		t.Fatalf("GenerateReport failed: %v", err)
 	}
 
@@ -295,6 +693,169 @@ func TestGenerateReport(t *testing.T) {
 	}
 }
 
+func TestRenderReport_AllStatuses(t *testing.T) {
+	dir := t.TempDir()
+	baselineDir := filepath.Join(dir, "baseline")
+	currentDir := filepath.Join(dir, "current")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+	createTestPNG(t, filepath.Join(baselineDir, "changed.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "changed.png"), 20, 20, red)
+	createTestPNG(t, filepath.Join(baselineDir, "same.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "same.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "new.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(baselineDir, "gone.png"), 20, 20, white)
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, results, ReportOptions{}); err != nil {
+		t.Fatalf("RenderReport failed: %v", err)
+	}
+
+	html := buf.String()
+	for _, expected := range []string{
+		"4 screenshots compared",
+		"changed.png",
+		"new.png",
+		"gone.png",
+		"same.png",
+		"badge-changed",
+		"badge-added",
+		"badge-removed",
+		`<div class="unchanged-item">same.png</div>`,
+		"100.00% changed",
+	} {
+		if !contains(html, expected) {
+			t.Errorf("rendered report missing expected content: %q", expected)
+		}
+	}
+}
+
+func TestRenderReport_DiffOnly(t *testing.T) {
+	dir := t.TempDir()
+	baselineDir := filepath.Join(dir, "baseline")
+	currentDir := filepath.Join(dir, "current")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+	createTestPNG(t, filepath.Join(baselineDir, "changed.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "changed.png"), 20, 20, red)
+	createTestPNG(t, filepath.Join(baselineDir, "same.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "same.png"), 20, 20, white)
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, results, ReportOptions{DiffOnly: true}); err != nil {
+		t.Fatalf("RenderReport failed: %v", err)
+	}
+
+	html := buf.String()
+	if !contains(html, "1 unchanged screenshot omitted") {
+		t.Error("expected a note about the omitted unchanged pair")
+	}
+	if contains(html, "same.png") {
+		t.Error("expected unchanged pair to be omitted from the report")
+	}
+	if !contains(html, "changed.png") {
+		t.Error("expected changed pair in the report")
+	}
+}
+
+func TestRenderReport_LinkFiles(t *testing.T) {
+	dir := t.TempDir()
+	baselineDir := filepath.Join(dir, "baseline")
+	currentDir := filepath.Join(dir, "current")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	red := color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+	createTestPNG(t, filepath.Join(baselineDir, "changed.png"), 20, 20, white)
+	createTestPNG(t, filepath.Join(currentDir, "changed.png"), 20, 20, red)
+
+	results, err := CompareDirectories(baselineDir, currentDir, CompareOptions{Threshold: 0.2})
+	if err != nil {
+		t.Fatalf("CompareDirectories failed: %v", err)
+	}
+	if err := SaveDiffImages(results, filepath.Join(dir, "diffs")); err != nil {
+		t.Fatalf("SaveDiffImages failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderReport(&buf, results, ReportOptions{LinkFiles: true}); err != nil {
+		t.Fatalf("RenderReport failed: %v", err)
+	}
+
+	html := buf.String()
+	if contains(html, "data:image/png;base64,") {
+		t.Error("expected no inlined images with LinkFiles")
+	}
+	for _, want := range []string{"file://" + filepath.ToSlash(currentDir), "changed.png.diff.png"} {
+		if !contains(html, want) {
+			t.Errorf("expected %q in the report", want)
+		}
+	}
+}
+
+func TestBuildSummary_Metrics(t *testing.T) {
+	results := []Result{
+		{Name: "a.png", Status: StatusChanged, DiffPercent: 10.0},
+		{Name: "b.png", Status: StatusChanged, DiffPercent: 30.0},
+		{Name: "c.png", Status: StatusAdded},
+		{Name: "d.png", Status: StatusUnchanged},
+	}
+
+	s := BuildSummary("admin", results)
+
+	if s.AverageDiffPercent != 20.0 {
+		t.Errorf("expected average diff 20.0, got %f", s.AverageDiffPercent)
+	}
+	if s.ChangedPercent != 75.0 {
+		t.Errorf("expected changed percent 75.0, got %f", s.ChangedPercent)
+	}
+
+	if s.Failures != 3 {
+		t.Errorf("expected 3 failures, got %d", s.Failures)
+	}
+	s.Ignore(true, false)
+	if s.Failures != 2 || s.Added != 1 {
+		t.Errorf("expected 2 failures with added ignored (added still counted), got %+v", s)
+	}
+
+	empty := BuildSummary("admin", nil)
+	if empty.AverageDiffPercent != 0 || empty.ChangedPercent != 0 {
+		t.Errorf("expected zero metrics for empty results, got %+v", empty)
+	}
+}
+
+func TestBuildSummary_ChangedPercentExcludesErrors(t *testing.T) {
+	results := []Result{
+		{Name: "a.png", Status: StatusChanged, DiffPercent: 10.0},
+		{Name: "b.png", Status: StatusError, Error: "decode failed"},
+		{Name: "c.png", Status: StatusUnchanged},
+		{Name: "d.png", Status: StatusUnchanged},
+	}
+
+	s := BuildSummary("admin", results)
+
+	if s.ChangedPercent != 25.0 {
+		t.Errorf("expected changed percent 25.0 (errors excluded), got %f", s.ChangedPercent)
+	}
+	if s.Errors != 1 || s.Failures != 2 {
+		t.Errorf("expected the error to count as a failure, got %+v", s)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && searchString(s, substr)
 }
@@ -307,3 +868,111 @@ func searchString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCompare_Masked(t *testing.T) {
+	dir := t.TempDir()
+	baselinePath := filepath.Join(dir, "baseline", "clock.png")
+	currentPath := filepath.Join(dir, "current", "clock.png")
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	black := color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	createTestPNG(t, baselinePath, 10, 10, white)
+	// The only difference is a 4x2 block at (0,0)
+	createTestPNGWithBlock(t, currentPath, 10, 10, white, black, 0, 0, 4, 2)
+
+	masks := Masks{
+		"clock*.png": {{X: 0, Y: 0, Width: 5, Height: 2}},
+		"other.png":  {{X: 5, Y: 5, Width: 5, Height: 5}},
+	}
+	result, err := Compare(baselinePath, currentPath, CompareOptions{Threshold: 0.2, Masks: masks})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Status != StatusUnchanged {
+		t.Errorf("expected unchanged with the block masked, got %s (%d pixels)", result.Status, result.DiffPixels)
+	}
+	if result.TotalPixels != 90 {
+		t.Errorf("TotalPixels = %d, want 90 (100 minus 10 masked)", result.TotalPixels)
+	}
+	if len(result.Masked) != 1 || result.Masked[0].String() != "0,0 5x2" {
+		t.Errorf("Masked = %v, want [0,0 5x2]", result.Masked)
+	}
+}
+
+func TestLoadMasks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "masks.json")
+	if err := os.WriteFile(path, []byte(`{"admin-*.png": [{"x": 1, "y": 2, "width": 3, "height": 4}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	masks, err := LoadMasks(path)
+	if err != nil {
+		t.Fatalf("LoadMasks failed: %v", err)
+	}
+	if got := masks.For("admin-users.png"); len(got) != 1 || got[0] != (Rect{X: 1, Y: 2, Width: 3, Height: 4}) {
+		t.Errorf("For(admin-users.png) = %v", got)
+	}
+	if got := masks.For("chat.png"); len(got) != 0 {
+		t.Errorf("For(chat.png) = %v, want none", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"a.png": [{"x": 0, "y": 0, "width": 0, "height": 4}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMasks(path); err == nil {
+		t.Error("expected an error for an empty region")
+	}
+}
+
+func TestParseMask(t *testing.T) {
+	pattern, r, err := ParseMask("admin-*.png:1100,8,160,32")
+	if err != nil {
+		t.Fatalf("ParseMask failed: %v", err)
+	}
+	if pattern != "admin-*.png" || r != (Rect{X: 1100, Y: 8, Width: 160, Height: 32}) {
+		t.Errorf("ParseMask = %q, %v", pattern, r)
+	}
+
+	for _, spec := range []string{"admin.png", ":1,2,3,4", "a.png:1,2,3", "a.png:1,2,0,4", "[.png:1,2,3,4"} {
+		if _, _, err := ParseMask(spec); err == nil {
+			t.Errorf("ParseMask(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []Result{
+		{Name: "changed.png", Status: StatusChanged, DiffPercent: 1.5, DiffPath: "diffs/changed.png.diff.png"},
+		{Name: "added.png", Status: StatusAdded, CurrentPath: "current/added.png"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteResults(&buf, results); err != nil {
+		t.Fatalf("WriteResults failed: %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(decoded))
+	}
+	if decoded[0]["name"] != "changed.png" || decoded[0]["status"] != "changed" {
+		t.Errorf("unexpected first result: %v", decoded[0])
+	}
+	if decoded[0]["diff_path"] != "diffs/changed.png.diff.png" {
+		t.Errorf("expected diff_path, got %v", decoded[0]["diff_path"])
+	}
+	if _, ok := decoded[1]["baseline_path"]; ok {
+		t.Errorf("expected baseline_path to be omitted for an added screenshot: %v", decoded[1])
+	}
+
+	buf.Reset()
+	if err := WriteResults(&buf, nil); err != nil {
+		t.Fatalf("WriteResults failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected [] for no results, got %q", buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package imgdiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes a compact Markdown summary of the results, suitable
+// for a GitHub PR comment: a one-line header with the counts followed by a
+// table of every pair that is not unchanged, in the order given
+// (CompareDirectories' sort order, as in the HTML report).
+func WriteMarkdown(w io.Writer, summary Summary, results []Result) error {
+	var b strings.Builder
+
+	title := "Visual regression"
+	if summary.Project != "" {
+		title += " (" + summary.Project + ")"
+	}
+	fmt.Fprintf(&b, "**%s:** %d changed · %d added · %d removed · %d unchanged",
+		title, summary.Changed, summary.Added, summary.Removed, summary.Unchanged)
+	if summary.Errors > 0 {
+		fmt.Fprintf(&b, " · %d error(s)", summary.Errors)
+	}
+	b.WriteString("\n\n")
+
+	if !summary.HasDifferences {
+		b.WriteString("No visual changes detected.\n")
+	} else {
+		b.WriteString("| Status | Screenshot | Diff |\n")
+		b.WriteString("|--------|------------|------|\n")
+		for _, r := range results {
+			var detail string
+			switch r.Status {
+			case StatusUnchanged:
+				continue
+			case StatusChanged:
+				detail = fmt.Sprintf("%.2f%%", r.DiffPercent)
+				if r.DimensionsChanged() {
+					detail += fmt.Sprintf(" (%s → %s)", r.BaselineSize, r.CurrentSize)
+				}
+			case StatusError:
+				detail = r.Error
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", r.Status, escapeMarkdownCell(r.Name), escapeMarkdownCell(detail))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// escapeMarkdownCell keeps s from breaking out of a table cell.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,55 @@
+package imgdiff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	results := []Result{
+		{Name: "broken.png", Status: StatusError, Error: "bad | header"},
+		{Name: "page.png", Status: StatusChanged, DiffPercent: 3.5},
+		{Name: "new.png", Status: StatusAdded},
+		{Name: "same.png", Status: StatusUnchanged},
+	}
+	summary := BuildSummary("admin", results)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, summary, results); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"**Visual regression (admin):** 1 changed · 1 added · 0 removed · 1 unchanged · 1 error(s)",
+		"| error | `broken.png` | bad \\| header |",
+		"| changed | `page.png` | 3.50% |",
+		"| added | `new.png` |  |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "same.png") {
+		t.Error("expected unchanged screenshots to be omitted from the table")
+	}
+	if strings.Index(out, "broken.png") > strings.Index(out, "page.png") {
+		t.Error("expected rows in the order given")
+	}
+}
+
+func TestWriteMarkdown_NoDifferences(t *testing.T) {
+	results := []Result{{Name: "same.png", Status: StatusUnchanged}}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, BuildSummary("admin", results), results); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No visual changes detected.") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "| Status |") {
+		t.Error("expected no table without differences")
+	}
+}
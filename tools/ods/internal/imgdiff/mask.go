@@ -0,0 +1,107 @@
+package imgdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Rect is a region of a screenshot in pixels, relative to its top-left corner.
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// String formats the rectangle as "x,y WxH".
+func (r Rect) String() string {
+	return fmt.Sprintf("%d,%d %dx%d", r.X, r.Y, r.Width, r.Height)
+}
+
+// contains reports whether the pixel (x, y) lies inside the rectangle.
+func (r Rect) contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// MaskFileName is the mask config picked up from a local current screenshots
+// directory when no config is given explicitly.
+const MaskFileName = "masks.json"
+
+// Masks maps screenshot-name globs (matched with filepath.Match against the
+// file name, e.g. "admin-*.png") to regions excluded from comparison. They
+// are for known-dynamic content such as timestamps and avatars.
+type Masks map[string][]Rect
+
+// LoadMasks reads a JSON mask config of the form
+//
+//	{"admin-*.png": [{"x": 0, "y": 0, "width": 200, "height": 40}]}
+func LoadMasks(path string) (Masks, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mask config: %w", err)
+	}
+	var masks Masks
+	if err := json.Unmarshal(data, &masks); err != nil {
+		return nil, fmt.Errorf("failed to parse mask config %s: %w", path, err)
+	}
+	for pattern, rects := range masks {
+		for _, r := range rects {
+			if err := validateMask(pattern, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return masks, nil
+}
+
+// ParseMask parses a --mask value of the form "pattern:x,y,w,h", e.g.
+// "admin-*.png:1100,8,160,32".
+func ParseMask(spec string) (string, Rect, error) {
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 {
+		return "", Rect{}, fmt.Errorf("invalid mask %q (want pattern:x,y,w,h)", spec)
+	}
+	pattern := spec[:i]
+	var r Rect
+	if _, err := fmt.Sscanf(spec[i+1:], "%d,%d,%d,%d", &r.X, &r.Y, &r.Width, &r.Height); err != nil {
+		return "", Rect{}, fmt.Errorf("invalid mask %q (want pattern:x,y,w,h): %w", spec, err)
+	}
+	if err := validateMask(pattern, r); err != nil {
+		return "", Rect{}, err
+	}
+	return pattern, r, nil
+}
+
+// validateMask checks that pattern is a valid glob and r is not empty.
+func validateMask(pattern string, r Rect) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid mask pattern %q: %w", pattern, err)
+	}
+	if r.Width <= 0 || r.Height <= 0 {
+		return fmt.Errorf("mask %q has an empty region %s", pattern, r)
+	}
+	return nil
+}
+
+// For returns the regions masked for the named screenshot, from every
+// matching pattern.
+func (m Masks) For(name string) []Rect {
+	patterns := make([]string, 0, len(m))
+	for pattern := range m {
+		patterns = append(patterns, pattern)
+	}
+	// Deterministic order so reports don't reshuffle between runs
+	sort.Strings(patterns)
+
+	var rects []Rect
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			rects = append(rects, m[pattern]...)
+		}
+	}
+	return rects
+}
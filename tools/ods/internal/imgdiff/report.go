@@ -7,8 +7,12 @@ import (
 	"html/template"
 	"image"
 	"image/png"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // reportEntry holds data for a single screenshot in the HTML template.
@@ -16,6 +20,10 @@ type reportEntry struct {
 	Name            string
 	Status          string
 	DiffPercent     string
+	Error           string
+	Masked          string
+	SSIM            string
+	Dimensions      string
 	BaselineDataURI template.URL
 	CurrentDataURI  template.URL
 	DiffDataURI     template.URL
@@ -24,6 +32,18 @@ type reportEntry struct {
 	HasDiff         bool
 }
 
+// ReportOptions controls what the HTML report includes.
+type ReportOptions struct {
+	// DiffOnly omits unchanged pairs from the report entirely. They are still
+	// counted in the header, but their images are not embedded.
+	DiffOnly bool
+
+	// LinkFiles references images by file:// URL instead of inlining them,
+	// for a much smaller report that only works while the images stay put.
+	// Diff overlays are linked via Result.DiffPath when set, else inlined.
+	LinkFiles bool
+}
+
 // reportData holds all data for the HTML template.
 type reportData struct {
 	Entries        []reportEntry
@@ -31,29 +51,60 @@ type reportData struct {
 	AddedCount     int
 	RemovedCount   int
 	UnchangedCount int
+	ErrorCount     int
 	TotalCount     int
 	HasDifferences bool
+	DiffOnly       bool
+
+	AverageDiffPercent string
+	ChangedPercent     string
 }
 
 // GenerateReport produces a self-contained HTML file from comparison results.
 // All images are base64-encoded inline as data URIs.
-func GenerateReport(results []Result, outputPath string) error {
+func GenerateReport(results []Result, outputPath string, opts ReportOptions) error {
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	data := reportData{}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return RenderReport(f, results, opts)
+}
+
+// RenderReport writes the self-contained HTML report for results to w.
+func RenderReport(w io.Writer, results []Result, opts ReportOptions) error {
+	data := reportData{DiffOnly: opts.DiffOnly}
 
 	for _, r := range results {
+		if opts.DiffOnly && r.Status == StatusUnchanged {
+			data.UnchangedCount++
+			continue
+		}
+
 		entry := reportEntry{
 			Name:   r.Name,
 			Status: r.Status.String(),
+			Masked: maskedLabel(r.Masked),
+		}
+		if r.DimensionsChanged() {
+			entry.Dimensions = r.BaselineSize.String() + " → " + r.CurrentSize.String()
+			if r.Resized {
+				entry.Dimensions += " (resized to compare)"
+			}
 		}
 
 		switch r.Status {
 		case StatusChanged:
 			data.ChangedCount++
 			entry.DiffPercent = fmt.Sprintf("%.2f%%", r.DiffPercent)
+			if r.SSIM > 0 {
+				entry.SSIM = fmt.Sprintf("%.4f", r.SSIM)
+			}
 		case StatusAdded:
 			data.AddedCount++
 		case StatusRemoved:
@@ -61,10 +112,16 @@ func GenerateReport(results []Result, outputPath string) error {
 		case StatusUnchanged:
 			data.UnchangedCount++
 			entry.DiffPercent = "0.00%"
+		case StatusError:
+			// The files may be unreadable, so don't try to embed them
+			data.ErrorCount++
+			entry.Error = r.Error
+			data.Entries = append(data.Entries, entry)
+			continue
 		}
 
 		if r.BaselinePath != "" {
-			uri, err := pngFileToDataURI(r.BaselinePath)
+			uri, err := imageURI(r.BaselinePath, opts.LinkFiles)
 			if err != nil {
 				return fmt.Errorf("failed to encode baseline %s: %w", r.Name, err)
 			}
@@ -73,7 +130,7 @@ func GenerateReport(results []Result, outputPath string) error {
 		}
 
 		if r.CurrentPath != "" {
-			uri, err := pngFileToDataURI(r.CurrentPath)
+			uri, err := imageURI(r.CurrentPath, opts.LinkFiles)
 			if err != nil {
 				return fmt.Errorf("failed to encode current %s: %w", r.Name, err)
 			}
@@ -81,7 +138,14 @@ func GenerateReport(results []Result, outputPath string) error {
 			entry.HasCurrent = true
 		}
 
-		if r.DiffImage != nil {
+		if opts.LinkFiles && r.DiffPath != "" {
+			uri, err := fileURL(r.DiffPath)
+			if err != nil {
+				return fmt.Errorf("failed to link diff %s: %w", r.Name, err)
+			}
+			entry.DiffDataURI = template.URL(uri)
+			entry.HasDiff = true
+		} else if r.DiffImage != nil {
 			uri, err := imageToDataURI(r.DiffImage)
 			if err != nil {
 				return fmt.Errorf("failed to encode diff %s: %w", r.Name, err)
@@ -94,34 +158,60 @@ func GenerateReport(results []Result, outputPath string) error {
 	}
 
 	data.TotalCount = len(results)
-	data.HasDifferences = data.ChangedCount > 0 || data.AddedCount > 0 || data.RemovedCount > 0
+	data.HasDifferences = data.ChangedCount > 0 || data.AddedCount > 0 || data.RemovedCount > 0 || data.ErrorCount > 0
+
+	summary := BuildSummary("", results)
+	data.AverageDiffPercent = fmt.Sprintf("%.2f%%", summary.AverageDiffPercent)
+	data.ChangedPercent = fmt.Sprintf("%.1f%%", summary.ChangedPercent)
 
 	tmpl, err := template.New("report").Parse(htmlTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	if err := tmpl.Execute(f, data); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	return nil
 }
 
-// pngFileToDataURI reads a PNG file and returns a base64 data URI.
-func pngFileToDataURI(path string) (string, error) {
+// maskedLabel lists masked regions for display, or "" if there are none.
+func maskedLabel(regions []Rect) string {
+	labels := make([]string, len(regions))
+	for i, r := range regions {
+		labels[i] = r.String()
+	}
+	return strings.Join(labels, "; ")
+}
+
+// imageURI returns a file:// URL for path when link is set, otherwise its
+// contents as a data URI.
+func imageURI(path string, link bool) (string, error) {
+	if link {
+		return fileURL(path)
+	}
+	return fileToDataURI(path)
+}
+
+// fileURL returns the absolute file:// URL of path.
+func fileURL(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+// fileToDataURI reads an image file and returns a base64 data URI, with the
+// MIME type sniffed from its content.
+func fileToDataURI(path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "", err
 	}
 	encoded := base64.StdEncoding.EncodeToString(data)
-	return "data:image/png;base64," + encoded, nil
+	return "data:" + http.DetectContentType(data) + ";base64," + encoded, nil
 }
 
 // imageToDataURI encodes an image.Image to a PNG base64 data URI.
@@ -151,6 +241,7 @@ const htmlTemplate = `<!DOCTYPE html>
   .summary-changed { background: #fff3e0; color: #e65100; }
   .summary-added { background: #e8f5e9; color: #2e7d32; }
   .summary-removed { background: #fce4ec; color: #c62828; }
+  .summary-error { background: #ede7f6; color: #4527a0; }
   .summary-unchanged { background: #e3f2fd; color: #1565c0; }
   .content { padding: 24px 32px; max-width: 1400px; margin: 0 auto; }
   .section-title { font-size: 18px; font-weight: 600; margin: 24px 0 16px; padding-bottom: 8px; border-bottom: 2px solid #e0e0e0; }
@@ -163,6 +254,10 @@ const htmlTemplate = `<!DOCTYPE html>
   .badge-changed { background: #fff3e0; color: #e65100; }
   .badge-added { background: #e8f5e9; color: #2e7d32; }
   .badge-removed { background: #fce4ec; color: #c62828; }
+  .badge-error { background: #ede7f6; color: #4527a0; }
+  .error-reason { padding: 16px 20px; font-family: monospace; font-size: 13px; color: #4527a0; white-space: pre-wrap; }
+  .masked-note { padding: 8px 20px; font-size: 13px; color: #666; background: #fafafa; border-bottom: 1px solid #eee; }
+  .dimension-note { padding: 8px 20px; font-size: 13px; color: #e65100; background: #fff8f0; border-bottom: 1px solid #eee; }
   .tabs { display: flex; gap: 0; border-bottom: 1px solid #eee; }
   .tab { padding: 10px 20px; cursor: pointer; font-size: 13px; font-weight: 500; color: #666; border-bottom: 2px solid transparent; transition: all 0.2s; }
   .tab:hover { color: #333; background: #f9f9f9; }
@@ -197,10 +292,11 @@ const htmlTemplate = `<!DOCTYPE html>
 
 <div class="header">
   <h1>Visual Regression Report</h1>
-  <p>{{.TotalCount}} screenshot{{if ne .TotalCount 1}}s{{end}} compared</p>
+  <p>{{.TotalCount}} screenshot{{if ne .TotalCount 1}}s{{end}} compared &middot; {{.ChangedPercent}} differ &middot; {{.AverageDiffPercent}} average diff across changed</p>
 </div>
 
 <div class="summary">
+  {{if gt .ErrorCount 0}}<div class="summary-card summary-error">{{.ErrorCount}} Error{{if ne .ErrorCount 1}}s{{end}}</div>{{end}}
   {{if gt .ChangedCount 0}}<div class="summary-card summary-changed">{{.ChangedCount}} Changed</div>{{end}}
   {{if gt .AddedCount 0}}<div class="summary-card summary-added">{{.AddedCount}} Added</div>{{end}}
   {{if gt .RemovedCount 0}}<div class="summary-card summary-removed">{{.RemovedCount}} Removed</div>{{end}}
@@ -216,12 +312,24 @@ const htmlTemplate = `<!DOCTYPE html>
 {{end}}
 
 {{range .Entries}}
+{{if eq .Status "error"}}
+<div class="card">
+  <div class="card-header">
+    <span class="card-name">{{.Name}}</span>
+    <span class="card-badge badge-error">error</span>
+  </div>
+  <div class="error-reason">{{.Error}}</div>
+</div>
+{{end}}
+
 {{if eq .Status "changed"}}
 <div class="card">
   <div class="card-header">
     <span class="card-name">{{.Name}}</span>
-    <span class="card-badge badge-changed">{{.DiffPercent}} changed</span>
+    <span class="card-badge badge-changed">{{.DiffPercent}} changed{{if .SSIM}} &middot; SSIM {{.SSIM}}{{end}}</span>
   </div>
+  {{if .Dimensions}}<div class="dimension-note">Dimensions changed: {{.Dimensions}}</div>{{end}}
+  {{if .Masked}}<div class="masked-note">Masked regions (ignored): {{.Masked}}</div>{{end}}
   <div class="tabs">
     <div class="tab active" onclick="switchTab(this, 'slider')">Slider</div>
     <div class="tab" onclick="switchTab(this, 'sidebyside')">Side by Side</div>
@@ -287,13 +395,17 @@ const htmlTemplate = `<!DOCTYPE html>
 {{end}}
 {{end}}
 
-{{if gt .UnchangedCount 0}}
+{{if and (gt .UnchangedCount 0) .DiffOnly}}
+<div class="unchanged-section">
+  <div class="unchanged-item">{{.UnchangedCount}} unchanged screenshot{{if ne .UnchangedCount 1}}s{{end}} omitted (--diff-only)</div>
+</div>
+{{else if gt .UnchangedCount 0}}
 <div class="unchanged-section">
   <div class="unchanged-toggle" onclick="toggleUnchanged(this)">
     &#9654; {{.UnchangedCount}} unchanged screenshot{{if ne .UnchangedCount 1}}s{{end}} (click to expand)
   </div>
   <div class="unchanged-list">
-    {{range .Entries}}{{if eq .Status "unchanged"}}<div class="unchanged-item">{{.Name}}</div>{{end}}{{end}}
+    {{range .Entries}}{{if eq .Status "unchanged"}}<div class="unchanged-item">{{.Name}}{{if .Masked}} &middot; masked: {{.Masked}}{{end}}</div>{{end}}{{end}}
   </div>
 </div>
 {{end}}
@@ -0,0 +1,91 @@
+package imgdiff
+
+import "fmt"
+
+// Mode selects how Compare decides whether a pair changed.
+type Mode string
+
+const (
+	// ModeExact marks a pair changed when any pixel differs beyond Threshold.
+	ModeExact Mode = "exact"
+	// ModeSSIM marks a pair changed when its structural similarity index
+	// falls below SSIMThreshold, which tolerates sub-pixel rendering noise.
+	ModeSSIM Mode = "ssim"
+)
+
+// DefaultSSIMThreshold is the SSIM score below which a pair counts as changed.
+const DefaultSSIMThreshold = 0.98
+
+// ssimWindow is the side of the square windows SSIM is averaged over.
+const ssimWindow = 8
+
+// SSIM stabilizing constants for 8-bit luminance: (0.01*255)^2 and (0.03*255)^2.
+const (
+	ssimC1 = 6.5025
+	ssimC2 = 58.5225
+)
+
+// ParseMode validates a --mode value.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeExact, ModeSSIM:
+		return m, nil
+	case "":
+		return ModeExact, nil
+	default:
+		return "", fmt.Errorf("invalid mode %q (valid: exact, ssim)", s)
+	}
+}
+
+// luminance returns the Rec. 601 luma of an 8-bit RGB color.
+func luminance(r, g, b float64) float64 {
+	return 0.299*r + 0.587*g + 0.114*b
+}
+
+// ssim returns the mean structural similarity of two luminance planes of the
+// given size, computed over non-overlapping ssimWindow-sized windows (the
+// last row and column of windows may be smaller). 1.0 means identical.
+func ssim(a, b []float64, width, height int) float64 {
+	var total float64
+	windows := 0
+	for wy := 0; wy < height; wy += ssimWindow {
+		for wx := 0; wx < width; wx += ssimWindow {
+			total += windowSSIM(a, b, width, wx, wy, min(wx+ssimWindow, width), min(wy+ssimWindow, height))
+			windows++
+		}
+	}
+	if windows == 0 {
+		return 1
+	}
+	return total / float64(windows)
+}
+
+// windowSSIM computes SSIM over the window [x0,x1) x [y0,y1).
+func windowSSIM(a, b []float64, stride, x0, y0, x1, y1 int) float64 {
+	n := float64((x1 - x0) * (y1 - y0))
+	var sumA, sumB float64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			sumA += a[y*stride+x]
+			sumB += b[y*stride+x]
+		}
+	}
+	meanA, meanB := sumA/n, sumB/n
+
+	var varA, varB, cov float64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			da := a[y*stride+x] - meanA
+			db := b[y*stride+x] - meanB
+			varA += da * da
+			varB += db * db
+			cov += da * db
+		}
+	}
+	varA /= n
+	varB /= n
+	cov /= n
+
+	return ((2*meanA*meanB + ssimC1) * (2*cov + ssimC2)) /
+		((meanA*meanA + meanB*meanB + ssimC1) * (varA + varB + ssimC2))
+}
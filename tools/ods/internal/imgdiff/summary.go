@@ -3,6 +3,7 @@ package imgdiff
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -16,30 +17,71 @@ type Summary struct {
 	Added          int    `json:"added"`
 	Removed        int    `json:"removed"`
 	Unchanged      int    `json:"unchanged"`
+	Errors         int    `json:"errors"`
 	Total          int    `json:"total"`
 	HasDifferences bool   `json:"has_differences"`
+
+	// AverageDiffPercent is the mean DiffPercent across changed pairs, and
+	// ChangedPercent is the share of all pairs that were changed, added, or
+	// removed. Errors count toward the total but not the share, since they say
+	// nothing about drift. Together they give a single number to track visual
+	// drift run over run.
+	AverageDiffPercent float64 `json:"average_diff_percent"`
+	ChangedPercent     float64 `json:"changed_percent"`
+
+	// Failures counts the results that should fail a gate. It equals
+	// Changed+Added+Removed+Errors unless added/removed screenshots are ignored.
+	Failures      int  `json:"failures"`
+	IgnoreAdded   bool `json:"ignore_added,omitempty"`
+	IgnoreRemoved bool `json:"ignore_removed,omitempty"`
 }
 
 // BuildSummary computes a Summary from a slice of comparison results.
 func BuildSummary(project string, results []Result) Summary {
 	s := Summary{Project: project}
+	var diffTotal float64
 	for _, r := range results {
 		switch r.Status {
 		case StatusChanged:
 			s.Changed++
+			diffTotal += r.DiffPercent
 		case StatusAdded:
 			s.Added++
 		case StatusRemoved:
 			s.Removed++
 		case StatusUnchanged:
 			s.Unchanged++
+		case StatusError:
+			s.Errors++
 		}
 	}
 	s.Total = len(results)
-	s.HasDifferences = s.Changed > 0 || s.Added > 0 || s.Removed > 0
+	s.HasDifferences = s.Changed > 0 || s.Added > 0 || s.Removed > 0 || s.Errors > 0
+	if s.Changed > 0 {
+		s.AverageDiffPercent = diffTotal / float64(s.Changed)
+	}
+	if s.Total > 0 {
+		s.ChangedPercent = float64(s.Changed+s.Added+s.Removed) / float64(s.Total) * 100.0
+	}
+	s.Failures = s.Changed + s.Added + s.Removed + s.Errors
 	return s
 }
 
+// Ignore excludes added and/or removed screenshots from Failures. They are
+// still counted (and listed in the report); they just no longer fail a gate.
+// Errors always count as failures.
+func (s *Summary) Ignore(added, removed bool) {
+	s.IgnoreAdded = added
+	s.IgnoreRemoved = removed
+	s.Failures = s.Changed + s.Errors
+	if !added {
+		s.Failures += s.Added
+	}
+	if !removed {
+		s.Failures += s.Removed
+	}
+}
+
 // WriteSummary writes a Summary as pretty-printed JSON to the given path,
 // creating parent directories as needed.
 func WriteSummary(summary Summary, path string) error {
@@ -58,3 +100,18 @@ func WriteSummary(summary Summary, path string) error {
 
 	return nil
 }
+
+// WriteResults writes comparison results as a pretty-printed JSON array, in
+// the order given (CompareDirectories' sort order). Diff images are not
+// embedded; DiffPath points at them once SaveDiffImages has run.
+func WriteResults(w io.Writer, results []Result) error {
+	if results == nil {
+		results = []Result{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package kube
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+)
+
+// awsCheckTTL is how long a successful AWS credentials check is trusted
+// before CheckAWSCredentials runs aws sts again.
+const awsCheckTTL = 5 * time.Minute
+
+// awsCheckStampPath returns the file whose mtime records the last successful
+// check. It is keyed by AWS_PROFILE so switching profiles re-checks.
+func awsCheckStampPath() string {
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	name := "aws-sts-ok-" + strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(profile)
+	return filepath.Join(paths.CacheDir(), name)
+}
+
+// CheckAWSCredentials verifies the AWS session with aws sts
+// get-caller-identity, so expired SSO logins fail before any kubectl or eks
+// call. Success is cached for a few minutes. A rejected session returns an
+// error matching ErrCredentialsExpired.
+func CheckAWSCredentials() error {
+	stamp := awsCheckStampPath()
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < awsCheckTTL {
+		log.Debug("AWS credentials checked recently, skipping aws sts get-caller-identity")
+		return nil
+	}
+
+	args := []string{"sts", "get-caller-identity", "--output", "json"}
+	cmd := exec.Command("aws", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("failed to run aws: %w", err)
+		}
+		// The AWS CLI reports expired or missing SSO sessions in several ways;
+		// any rejection here means the user needs to log in again
+		return fmt.Errorf("aws sts get-caller-identity failed: %s: %w",
+			strings.TrimSpace(stderr.String()), ErrCredentialsExpired)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(stamp), 0755); err == nil {
+		_ = os.WriteFile(stamp, nil, 0644)
+	}
+	return nil
+}
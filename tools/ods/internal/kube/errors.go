@@ -0,0 +1,74 @@
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrPodNotFound is returned by FindPod when no ready pod matches.
+	ErrPodNotFound = errors.New("pod not found")
+
+	// ErrCredentialsExpired matches an ExecError caused by expired or missing
+	// cluster credentials (e.g. an expired AWS SSO session).
+	ErrCredentialsExpired = errors.New("cluster credentials expired")
+)
+
+// credentialMarkers are lowercase substrings of kubectl stderr that indicate
+// the cluster rejected our credentials rather than the command failing.
+var credentialMarkers = []string{
+	"token has expired",
+	"expiredtoken",
+	"sso session",
+	"the server has asked for the client to provide credentials",
+	"unauthorized",
+	"you must be logged in",
+}
+
+// ExecError is returned when a kubectl invocation fails. Stderr holds the
+// captured error output.
+type ExecError struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *ExecError) Error() string {
+	msg := fmt.Sprintf("kubectl %s failed: %v", kubectlVerb(e.Args), e.Err)
+	if e.Stderr != "" {
+		msg += "\n" + e.Stderr
+	}
+	return msg
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrCredentialsExpired when stderr shows an auth failure.
+func (e *ExecError) Is(target error) bool {
+	if target != ErrCredentialsExpired {
+		return false
+	}
+	lower := strings.ToLower(e.Stderr)
+	for _, m := range credentialMarkers {
+		if strings.Contains(lower, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectlVerb returns the kubectl subcommand (e.g. "exec") from args that may
+// start with --context/--namespace flags.
+func kubectlVerb(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			i++ // skip the flag's value
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExecError_CredentialsExpired(t *testing.T) {
+	expired := &ExecError{
+		Args:   []string{"--context", "prod", "--namespace", "onyx", "exec", "api-server-0"},
+		Stderr: "error: You must be logged in to the server (the server has asked for the client to provide credentials)",
+		Err:    errors.New("exit status 1"),
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", expired), ErrCredentialsExpired) {
+		t.Error("expected expired-credentials stderr to match ErrCredentialsExpired")
+	}
+	if !strings.HasPrefix(expired.Error(), "kubectl exec failed") {
+		t.Errorf("unexpected message: %q", expired.Error())
+	}
+
+	queryFailed := &ExecError{Stderr: `ERROR:  relation "user" does not exist`, Err: errors.New("exit status 1")}
+	if errors.Is(queryFailed, ErrCredentialsExpired) {
+		t.Error("query failure should not match ErrCredentialsExpired")
+	}
+}
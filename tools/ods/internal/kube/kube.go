@@ -3,12 +3,22 @@ package kube
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
 
+var dryRun bool
+
+// SetDryRun makes ExecOnPod log the kubectl command it would run and return
+// empty output without running it. Called from the root command's
+// PersistentPreRun.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
 // Cluster holds the connection info for a Kubernetes cluster.
 type Cluster struct {
 	Name      string
@@ -50,10 +60,11 @@ func (c *Cluster) FindPod(substring string) (string, error) {
 	cmd := exec.Command("kubectl", args...)
 	out, err := cmd.Output()
 	if err != nil {
+		execErr := &ExecError{Args: args, Err: err}
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("kubectl get po failed: %w\n%s", err, string(exitErr.Stderr))
+			execErr.Stderr = strings.TrimSpace(string(exitErr.Stderr))
 		}
-		return "", fmt.Errorf("kubectl get po failed: %w", err)
+		return "", execErr
 	}
 
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
@@ -68,13 +79,18 @@ func (c *Cluster) FindPod(substring string) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("no ready pod found matching %q", substring)
+	return "", fmt.Errorf("no ready pod matching %q: %w", substring, ErrPodNotFound)
 }
 
-// ExecOnPod runs a command on a pod and returns its stdout.
+// ExecOnPod runs a command on a pod and returns its stdout. Failures are
+// returned as *ExecError.
 func (c *Cluster) ExecOnPod(pod string, command ...string) (string, error) {
 	args := append(c.kubectlArgs(), "exec", pod, "--")
 	args = append(args, command...)
+	if dryRun {
+		log.Infof("[DRY RUN] kubectl %s", strings.Join(args, " "))
+		return "", nil
+	}
 	log.Debugf("Running: kubectl %s", strings.Join(args, " "))
 
 	cmd := exec.Command("kubectl", args...)
@@ -83,8 +99,36 @@ func (c *Cluster) ExecOnPod(pod string, command ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("kubectl exec failed: %w\n%s", err, stderr.String())
+		return "", &ExecError{Args: args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
 	}
 
 	return stdout.String(), nil
 }
+
+// ExecInteractive runs a command on a pod attached to the terminal, like
+// kubectl exec -it. stdin and tty control the -i and -t flags.
+func (c *Cluster) ExecInteractive(pod string, stdin, tty bool, command ...string) error {
+	args := append(c.kubectlArgs(), "exec")
+	if stdin {
+		args = append(args, "-i")
+	}
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, pod, "--")
+	args = append(args, command...)
+	if dryRun {
+		log.Infof("[DRY RUN] kubectl %s", strings.Join(args, " "))
+		return nil
+	}
+	log.Debugf("Running: kubectl %s", strings.Join(args, " "))
+
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return &ExecError{Args: args, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package lazyimports
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+)
+
+// BaselineFileName is the file, relative to the backend directory, that lists
+// known violations. It is committed so changes to it are reviewed in PRs.
+const BaselineFileName = ".lazy_imports_baseline"
+
+const baselineHeader = `# Known eager imports of lazy modules, one "<file>: <import>" per line.
+# ods check-lazy-imports --baseline only fails on violations not listed here.
+# Regenerate with: ods check-lazy-imports --update-baseline
+`
+
+// Baseline counts known violations by file and import statement. Line numbers
+// are left out so unrelated edits don't invalidate entries.
+type Baseline map[string]int
+
+// baselineKey identifies a violation line independent of its line number.
+func baselineKey(relPath string, line ViolationLine) string {
+	return filepath.ToSlash(relPath) + ": " + strings.TrimSpace(line.Content)
+}
+
+// BaselinePath returns the path of the baseline file in the backend directory.
+func BaselinePath() (string, error) {
+	backendDir, err := paths.BackendDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(backendDir, BaselineFileName), nil
+}
+
+// LoadBaseline reads a baseline file. A missing file is an empty baseline.
+func LoadBaseline(path string) (Baseline, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Baseline{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	baseline := Baseline{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		baseline[line]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	return baseline, nil
+}
+
+// WriteBaseline writes every violation line to a baseline file, sorted so the
+// file diffs cleanly.
+func WriteBaseline(path string, violations []FileViolation) error {
+	var lines []string
+	seen := map[string]bool{}
+	for _, v := range violations {
+		for _, line := range v.ViolationLines {
+			// A line matching several modules is reported once per module
+			id := fmt.Sprintf("%s:%d", v.RelPath, line.LineNum)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			lines = append(lines, baselineKey(v.RelPath, line))
+		}
+	}
+	sort.Strings(lines)
+
+	content := baselineHeader
+	if len(lines) > 0 {
+		content += strings.Join(lines, "\n") + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of violations in the baseline.
+func (b Baseline) Len() int {
+	n := 0
+	for _, count := range b {
+		n += count
+	}
+	return n
+}
+
+// FilterNew returns the violations not covered by the baseline, and how many
+// violation lines the baseline accounted for.
+func (b Baseline) FilterNew(violations []FileViolation) ([]FileViolation, int) {
+	remaining := make(Baseline, len(b))
+	for k, v := range b {
+		remaining[k] = v
+	}
+
+	var fresh []FileViolation
+	known := 0
+	for _, v := range violations {
+		var lines []ViolationLine
+		modules := map[string]struct{}{}
+		allowed := map[int]bool{}
+		for _, line := range v.ViolationLines {
+			key := baselineKey(v.RelPath, line)
+			// Lines matching several modules share one baseline entry
+			if ok, seen := allowed[line.LineNum]; seen {
+				if !ok {
+					lines = append(lines, line)
+					modules[line.Module] = struct{}{}
+				}
+				continue
+			}
+			if remaining[key] > 0 {
+				remaining[key]--
+				allowed[line.LineNum] = true
+				known++
+				continue
+			}
+			allowed[line.LineNum] = false
+			lines = append(lines, line)
+			modules[line.Module] = struct{}{}
+		}
+		if len(lines) > 0 {
+			fresh = append(fresh, FileViolation{
+				RelPath:         v.RelPath,
+				ViolationLines:  lines,
+				ViolatedModules: modules,
+			})
+		}
+	}
+	return fresh, known
+}
@@ -0,0 +1,66 @@
+package lazyimports
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBaselineFilterNew(t *testing.T) {
+	known := []FileViolation{{
+		RelPath: "onyx/llm/utils.py",
+		ViolationLines: []ViolationLine{
+			{LineNum: 3, Content: "import litellm", Module: "litellm"},
+		},
+	}}
+	path := filepath.Join(t.TempDir(), BaselineFileName)
+	if err := WriteBaseline(path, known); err != nil {
+		t.Fatalf("WriteBaseline: %v", err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+	if baseline.Len() != 1 {
+		t.Fatalf("baseline.Len() = %d, want 1", baseline.Len())
+	}
+
+	// The known import moved lines and a new one was added
+	current := []FileViolation{
+		{
+			RelPath: "onyx/llm/utils.py",
+			ViolationLines: []ViolationLine{
+				{LineNum: 10, Content: "import litellm", Module: "litellm"},
+				{LineNum: 11, Content: "import tiktoken", Module: "tiktoken"},
+			},
+		},
+		{
+			RelPath: "onyx/chat/chat.py",
+			ViolationLines: []ViolationLine{
+				{LineNum: 1, Content: "import litellm", Module: "litellm"},
+			},
+		},
+	}
+	fresh, knownCount := baseline.FilterNew(current)
+	if knownCount != 1 {
+		t.Errorf("known = %d, want 1", knownCount)
+	}
+	if len(fresh) != 2 {
+		t.Fatalf("got %d files with new violations, want 2: %+v", len(fresh), fresh)
+	}
+	if len(fresh[0].ViolationLines) != 1 || fresh[0].ViolationLines[0].LineNum != 11 {
+		t.Errorf("unexpected new violations in utils.py: %+v", fresh[0].ViolationLines)
+	}
+	if _, ok := fresh[0].ViolatedModules["tiktoken"]; !ok || len(fresh[0].ViolatedModules) != 1 {
+		t.Errorf("ViolatedModules = %v, want only tiktoken", fresh[0].ViolatedModules)
+	}
+}
+
+func TestLoadBaselineMissingFile(t *testing.T) {
+	baseline, err := LoadBaseline(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+	if baseline.Len() != 0 {
+		t.Errorf("baseline.Len() = %d, want 0", baseline.Len())
+	}
+}
@@ -0,0 +1,517 @@
+package lazyimports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/onyx-dot-app/onyx/tools/ods/internal/paths"
+)
+
+// FixResult describes the fixes applied to one file.
+type FixResult struct {
+	RelPath string
+	Fixed   []ViolationLine
+	Skipped []SkippedFix
+}
+
+// SkippedFix is a violation that could not be fixed automatically.
+type SkippedFix struct {
+	Line   ViolationLine
+	Reason string
+}
+
+// FixLazyImports rewrites the eager imports in violations where it can do so
+// mechanically:
+//   - an import only used inside function bodies is moved into each function
+//     that uses it;
+//   - an import also used in annotations of a file with
+//     "from __future__ import annotations" is moved under "if TYPE_CHECKING:"
+//     and imported locally in the functions that use it at runtime.
+//
+// Anything else (module-level use, imports inside module-level blocks,
+// multi-line or multi-module imports) is left alone and reported as skipped.
+func FixLazyImports(violations []FileViolation) ([]FixResult, error) {
+	backendDir, err := paths.BackendDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []FixResult
+	for _, v := range violations {
+		path := filepath.Join(backendDir, v.RelPath)
+		info, err := os.Stat(path)
+		if err != nil {
+			return results, fmt.Errorf("failed to stat %s: %w", v.RelPath, err)
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return results, fmt.Errorf("failed to read %s: %w", v.RelPath, err)
+		}
+
+		fixed, result := fixSource(string(src), v.ViolationLines)
+		result.RelPath = v.RelPath
+		if len(result.Fixed) > 0 {
+			if err := os.WriteFile(path, []byte(fixed), info.Mode().Perm()); err != nil {
+				return results, fmt.Errorf("failed to write %s: %w", v.RelPath, err)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// sourceLine is one physical line of a Python file.
+type sourceLine struct {
+	text string
+	// code is text with comments and (non f-string) string contents blanked
+	// out, so names are only matched in code.
+	code string
+	// logical is false for lines that continue a statement (open brackets,
+	// backslash continuation, or inside a triple-quoted string).
+	logical bool
+	indent  int
+}
+
+// blank reports whether the line has no code.
+func (l sourceLine) blank() bool {
+	return strings.TrimSpace(l.code) == ""
+}
+
+// lexPython splits src into lines and tracks enough of Python's lexical
+// structure (strings, comments, brackets, continuations) to find statement
+// boundaries and name uses.
+func lexPython(src string) []sourceLine {
+	texts := strings.Split(src, "\n")
+	lines := make([]sourceLine, len(texts))
+
+	var triple string // closing quotes of an open triple-quoted string
+	tripleF := false  // whether that string is an f-string
+	depth := 0
+	continued := false
+
+	for n, text := range texts {
+		line := sourceLine{
+			text:    text,
+			logical: triple == "" && depth == 0 && !continued,
+			indent:  len(text) - len(strings.TrimLeft(text, " \t")),
+		}
+		code := []byte(text)
+		blankRange := func(from, to int) {
+			for k := from; k < to && k < len(code); k++ {
+				code[k] = ' '
+			}
+		}
+
+		for i := 0; i < len(text); {
+			if triple != "" {
+				if strings.HasPrefix(text[i:], triple) {
+					triple = ""
+					i += 3
+					continue
+				}
+				if !tripleF {
+					blankRange(i, i+1)
+				}
+				if text[i] == '\\' && i+1 < len(text) {
+					if !tripleF {
+						blankRange(i+1, i+2)
+					}
+					i += 2
+					continue
+				}
+				i++
+				continue
+			}
+
+			switch c := text[i]; c {
+			case '#':
+				blankRange(i, len(text))
+				i = len(text)
+				continue
+			case '"', '\'':
+				isF := stringPrefixHasF(text[:i])
+				q := string([]byte{c, c, c})
+				if strings.HasPrefix(text[i:], q) {
+					triple, tripleF = q, isF
+					i += 3
+					continue
+				}
+				end := i + 1
+				for end < len(text) && text[end] != c {
+					if text[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if !isF {
+					blankRange(i+1, end)
+				}
+				i = end + 1
+				continue
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if depth > 0 {
+					depth--
+				}
+			}
+			i++
+		}
+
+		line.code = string(code)
+		continued = triple == "" && strings.HasSuffix(strings.TrimRight(line.code, " \t"), "\\")
+		lines[n] = line
+	}
+	return lines
+}
+
+// stringPrefixHasF reports whether the string literal starting after before
+// has an f prefix (f"", rf"", Fr"", ...).
+func stringPrefixHasF(before string) bool {
+	for i := len(before) - 1; i >= 0 && len(before)-i <= 2; i-- {
+		switch before[i] {
+		case 'f', 'F':
+			return i == 0 || !isIdentByte(before[i-1]) || strings.ContainsRune("rR", rune(before[i-1]))
+		case 'r', 'R', 'b', 'B', 'u', 'U':
+			continue
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// pythonScopes returns, for each line, the index of the line of the innermost
+// enclosing def (or -1 at module and class level), and whether the line is
+// part of a def header.
+func pythonScopes(lines []sourceLine) (owner []int, header []bool) {
+	type block struct {
+		indent int
+		line   int
+		isDef  bool
+	}
+	var stack []block
+	owner = make([]int, len(lines))
+	header = make([]bool, len(lines))
+
+	curOwner, inHeader := -1, false
+	for i, l := range lines {
+		if !l.logical || l.blank() {
+			owner[i] = curOwner
+			header[i] = inHeader && !l.logical
+			continue
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent >= l.indent {
+			stack = stack[:len(stack)-1]
+		}
+		curOwner = -1
+		for k := len(stack) - 1; k >= 0; k-- {
+			if stack[k].isDef {
+				curOwner = stack[k].line
+				break
+			}
+		}
+
+		stmt := strings.TrimSpace(l.code)
+		isDef := strings.HasPrefix(stmt, "def ") || strings.HasPrefix(stmt, "async def ")
+		inHeader = isDef
+		owner[i], header[i] = curOwner, isDef
+		if isDef || strings.HasPrefix(stmt, "class ") {
+			stack = append(stack, block{indent: l.indent, line: i, isDef: isDef})
+		}
+	}
+	return owner, header
+}
+
+// importName is one name bound by an import statement.
+type importName struct {
+	text  string // as written, e.g. "b as c"
+	bound string // the name it binds, e.g. "c"
+}
+
+// importStmt is a parsed single-line import statement.
+type importStmt struct {
+	from   string // module for "from ... import ..."; empty for "import ..."
+	names  []importName
+	source string // the statement without indentation or comment
+}
+
+// statement returns the import statement limited to the given bound names.
+func (s importStmt) statement(bound map[string]bool) string {
+	if s.from == "" {
+		return s.source
+	}
+	var texts []string
+	for _, n := range s.names {
+		if bound[n.bound] {
+			texts = append(texts, n.text)
+		}
+	}
+	return "from " + s.from + " import " + strings.Join(texts, ", ")
+}
+
+var (
+	importAsPattern = regexp.MustCompile(`^([\w.]+)(?:\s+as\s+(\w+))?$`)
+	fromPattern     = regexp.MustCompile(`^from\s+([\w.]+)\s+import\s+(.+)$`)
+)
+
+// parseImport parses a module-level import line. Statements the fixer can't
+// rewrite safely return an error describing why.
+func parseImport(code string) (importStmt, error) {
+	stmt := strings.Join(strings.Fields(code), " ")
+	switch {
+	case strings.ContainsAny(stmt, "();\\"):
+		return importStmt{}, fmt.Errorf("multi-line or compound import")
+	case strings.HasPrefix(stmt, "import "):
+		m := importAsPattern.FindStringSubmatch(strings.TrimPrefix(stmt, "import "))
+		if m == nil {
+			return importStmt{}, fmt.Errorf("imports several modules")
+		}
+		bound := m[2]
+		if bound == "" {
+			bound, _, _ = strings.Cut(m[1], ".")
+		}
+		return importStmt{names: []importName{{text: m[1], bound: bound}}, source: stmt}, nil
+	}
+
+	m := fromPattern.FindStringSubmatch(stmt)
+	if m == nil {
+		return importStmt{}, fmt.Errorf("unrecognized import")
+	}
+	parsed := importStmt{from: m[1], source: stmt}
+	for _, item := range strings.Split(m[2], ",") {
+		item = strings.TrimSpace(item)
+		im := importAsPattern.FindStringSubmatch(item)
+		if im == nil || strings.Contains(im[1], ".") {
+			return importStmt{}, fmt.Errorf("unsupported import %q", item)
+		}
+		bound := im[1]
+		if im[2] != "" {
+			bound = im[2]
+		}
+		parsed.names = append(parsed.names, importName{text: item, bound: bound})
+	}
+	return parsed, nil
+}
+
+// nameUses classifies where an import's names are used.
+type nameUses struct {
+	// funcs maps a def line to the names its body uses.
+	funcs map[int]map[string]bool
+	// annotations is true if a name is used in a module-level def header
+	// annotation.
+	annotations bool
+	// moduleLevel is the first other use at import time, or -1.
+	moduleLevel int
+}
+
+// findUses finds every use of the names bound by stmt outside its own line.
+func findUses(lines []sourceLine, owner []int, header []bool, importLine int, stmt importStmt) nameUses {
+	uses := nameUses{funcs: map[int]map[string]bool{}, moduleLevel: -1}
+	for _, name := range stmt.names {
+		pattern := regexp.MustCompile(`(^|[^\w.])` + regexp.QuoteMeta(name.bound) + `\b`)
+		for i, l := range lines {
+			if i == importLine {
+				continue
+			}
+			for _, loc := range pattern.FindAllStringIndex(l.code, -1) {
+				switch {
+				case owner[i] >= 0:
+					if uses.funcs[owner[i]] == nil {
+						uses.funcs[owner[i]] = map[string]bool{}
+					}
+					uses.funcs[owner[i]][name.bound] = true
+				case header[i] && isAnnotation(l.code[:loc[0]+1]):
+					uses.annotations = true
+				case uses.moduleLevel < 0:
+					uses.moduleLevel = i
+				}
+			}
+		}
+	}
+	return uses
+}
+
+// isAnnotation reports whether a name following before on a def header line
+// is in a parameter or return annotation rather than a default value.
+func isAnnotation(before string) bool {
+	if i := strings.LastIndex(before, "->"); i >= 0 {
+		// A colon after the arrow ends the header ("-> int: return x").
+		return !strings.Contains(before[i:], ":")
+	}
+	param := before[strings.LastIndexAny(before, "(,")+1:]
+	return strings.Contains(param, ":") && !strings.ContainsAny(param, "=)")
+}
+
+// bodyStart returns the line before which a statement can be inserted at the
+// top of the function defined at line def (after its docstring), and the
+// indentation to use.
+func bodyStart(lines []sourceLine, def int) (int, string, bool) {
+	end := def
+	for end+1 < len(lines) && !lines[end+1].logical {
+		end++
+	}
+	first := end + 1
+	for first < len(lines) && lines[first].blank() {
+		first++
+	}
+	if first >= len(lines) || lines[first].indent <= lines[def].indent {
+		return 0, "", false
+	}
+
+	indent := lines[first].text[:lines[first].indent]
+	insert := first
+	if docstringPattern.MatchString(strings.TrimSpace(lines[first].text)) {
+		insert++
+		for insert < len(lines) && !lines[insert].logical {
+			insert++
+		}
+	}
+	return insert, indent, true
+}
+
+var docstringPattern = regexp.MustCompile(`^[rRuU]?("|')`)
+
+// functionHasLine reports whether the function defined at line def already
+// contains stmt as a line of its own.
+func functionHasLine(lines []sourceLine, owner []int, def int, stmt string) bool {
+	for i := def + 1; i < len(lines); i++ {
+		if lines[i].logical && !lines[i].blank() && lines[i].indent <= lines[def].indent {
+			break
+		}
+		if owner[i] == def && strings.TrimSpace(lines[i].code) == stmt {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	futureAnnotationsPattern = regexp.MustCompile(`^from\s+__future__\s+import\s+.*\bannotations\b`)
+	typeCheckingPattern      = regexp.MustCompile(`^from\s+typing\s+import\s+.*\bTYPE_CHECKING\b`)
+)
+
+// fixSource applies the fixes for the given violation lines to src and
+// returns the new source with what was fixed and skipped.
+func fixSource(src string, violations []ViolationLine) (string, FixResult) {
+	lines := lexPython(src)
+	owner, header := pythonScopes(lines)
+
+	var hasFutureAnnotations, hasTypeChecking bool
+	for _, l := range lines {
+		if l.logical && l.indent == 0 {
+			code := strings.TrimSpace(l.code)
+			hasFutureAnnotations = hasFutureAnnotations || futureAnnotationsPattern.MatchString(code)
+			hasTypeChecking = hasTypeChecking || typeCheckingPattern.MatchString(code)
+		}
+	}
+
+	var result FixResult
+	// replace maps an import line to its replacement lines (nil deletes it);
+	// insert maps a line to the lines inserted before it.
+	replace := map[int][]string{}
+	insert := map[int][]string{}
+	seen := map[int]bool{}
+
+	for _, v := range violations {
+		idx := v.LineNum - 1
+		if seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, SkippedFix{Line: v, Reason: reason})
+		}
+
+		if idx < 0 || idx >= len(lines) || lines[idx].text != v.Content {
+			skip("file changed since it was checked")
+			continue
+		}
+		if lines[idx].indent != 0 {
+			skip("inside a module-level block")
+			continue
+		}
+		stmt, err := parseImport(lines[idx].code)
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+		uses := findUses(lines, owner, header, idx, stmt)
+		switch {
+		case uses.moduleLevel >= 0:
+			skip(fmt.Sprintf("used at module level on line %d", uses.moduleLevel+1))
+			continue
+		case uses.annotations && !hasFutureAnnotations:
+			skip("used in annotations evaluated at import time (add \"from __future__ import annotations\")")
+			continue
+		case !uses.annotations && len(uses.funcs) == 0:
+			skip("never used; remove it or import it where it is needed")
+			continue
+		}
+
+		type insertion struct {
+			line int
+			text string
+		}
+		var insertions []insertion
+		ok := true
+		for def, names := range uses.funcs {
+			local := stmt.statement(names)
+			if functionHasLine(lines, owner, def, local) {
+				continue
+			}
+			at, indent, found := bodyStart(lines, def)
+			if !found {
+				skip(fmt.Sprintf("could not find the body of the function on line %d", def+1))
+				ok = false
+				break
+			}
+			insertions = append(insertions, insertion{at, indent + local})
+		}
+		if !ok {
+			continue
+		}
+		for _, ins := range insertions {
+			insert[ins.line] = append(insert[ins.line], ins.text)
+		}
+
+		if uses.annotations {
+			var block []string
+			if !hasTypeChecking {
+				block = append(block, "from typing import TYPE_CHECKING")
+				hasTypeChecking = true
+			}
+			block = append(block, "if TYPE_CHECKING:", "    "+strings.TrimSpace(lines[idx].text))
+			replace[idx] = block
+		} else {
+			replace[idx] = nil
+		}
+		result.Fixed = append(result.Fixed, v)
+	}
+
+	if len(result.Fixed) == 0 {
+		return src, result
+	}
+
+	out := make([]string, 0, len(lines))
+	for i, l := range lines {
+		added := insert[i]
+		sort.Strings(added)
+		out = append(out, added...)
+		if r, ok := replace[i]; ok {
+			out = append(out, r...)
+			continue
+		}
+		out = append(out, l.text)
+	}
+	out = append(out, insert[len(lines)]...)
+	return strings.Join(out, "\n"), result
+}
@@ -0,0 +1,180 @@
+package lazyimports
+
+import (
+	"strings"
+	"testing"
+)
+
+// violationsFor returns a ViolationLine for each line of src containing one
+// of the given import statements.
+func violationsFor(src string, imports ...string) []ViolationLine {
+	var violations []ViolationLine
+	for i, line := range strings.Split(src, "\n") {
+		for _, imp := range imports {
+			if strings.TrimSpace(line) == imp {
+				violations = append(violations, ViolationLine{LineNum: i + 1, Content: line})
+			}
+		}
+	}
+	return violations
+}
+
+func TestFixSourceMovesImportIntoFunctions(t *testing.T) {
+	src := `import os
+import openai
+from tiktoken import get_encoding, Encoding as Enc
+
+
+def complete(prompt: str) -> str:
+    """Complete a prompt.
+
+    Mentions openai in the docstring only.
+    """
+    client = openai.OpenAI()
+    return client.complete(prompt)
+
+
+class Counter:
+    def count(self, text):
+        # tiktoken is only needed here
+        return len(get_encoding("cl100k").encode(text))
+
+    def name(self):
+        return "openai"
+`
+	want := `import os
+
+
+def complete(prompt: str) -> str:
+    """Complete a prompt.
+
+    Mentions openai in the docstring only.
+    """
+    import openai
+    client = openai.OpenAI()
+    return client.complete(prompt)
+
+
+class Counter:
+    def count(self, text):
+        # tiktoken is only needed here
+        from tiktoken import get_encoding
+        return len(get_encoding("cl100k").encode(text))
+
+    def name(self):
+        return "openai"
+`
+	got, result := fixSource(src, violationsFor(src, "import openai", "from tiktoken import get_encoding, Encoding as Enc"))
+	if len(result.Fixed) != 2 || len(result.Skipped) != 0 {
+		t.Fatalf("fixed %d, skipped %v; want 2 fixed", len(result.Fixed), result.Skipped)
+	}
+	if got != want {
+		t.Errorf("fixSource() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFixSourceTypeChecking(t *testing.T) {
+	src := `from __future__ import annotations
+
+from openai import OpenAI
+
+
+def build(
+    key: str,
+) -> OpenAI:
+    return OpenAI(api_key=key)
+
+
+def describe(client: OpenAI) -> str:
+    return str(client)
+`
+	want := `from __future__ import annotations
+
+from typing import TYPE_CHECKING
+if TYPE_CHECKING:
+    from openai import OpenAI
+
+
+def build(
+    key: str,
+) -> OpenAI:
+    from openai import OpenAI
+    return OpenAI(api_key=key)
+
+
+def describe(client: OpenAI) -> str:
+    return str(client)
+`
+	got, result := fixSource(src, violationsFor(src, "from openai import OpenAI"))
+	if len(result.Fixed) != 1 {
+		t.Fatalf("skipped %v; want fixed", result.Skipped)
+	}
+	if got != want {
+		t.Errorf("fixSource() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFixSourceSkips(t *testing.T) {
+	tests := map[string]struct {
+		src, imp, reason string
+	}{
+		"module level use": {
+			src:    "import openai\n\nclient = openai.OpenAI()\n",
+			imp:    "import openai",
+			reason: "module level",
+		},
+		"base class": {
+			src:    "from openai import OpenAI\n\n\nclass Client(OpenAI):\n    pass\n",
+			imp:    "from openai import OpenAI",
+			reason: "module level",
+		},
+		"default value": {
+			src:    "from __future__ import annotations\nimport openai\n\n\ndef f(model=openai.DEFAULT):\n    return model\n",
+			imp:    "import openai",
+			reason: "module level",
+		},
+		"annotation without future import": {
+			src:    "from openai import OpenAI\n\n\ndef f(client: OpenAI):\n    return client\n",
+			imp:    "from openai import OpenAI",
+			reason: "annotations",
+		},
+		"unused": {
+			src:    "import litellm\n",
+			imp:    "import litellm",
+			reason: "never used",
+		},
+		"multi-line": {
+			src:    "from openai import (\n    OpenAI,\n)\n",
+			imp:    "from openai import (",
+			reason: "multi-line",
+		},
+		"inside try block": {
+			src:    "try:\n    import openai\nexcept ImportError:\n    pass\n\n\ndef f():\n    return openai\n",
+			imp:    "import openai",
+			reason: "module-level block",
+		},
+		"several modules": {
+			src:    "import openai, os\n\n\ndef f():\n    return openai\n",
+			imp:    "import openai, os",
+			reason: "several modules",
+		},
+	}
+	for name, tt := range tests {
+		got, result := fixSource(tt.src, violationsFor(tt.src, tt.imp))
+		if got != tt.src {
+			t.Errorf("%s: source changed to\n%s", name, got)
+		}
+		if len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, tt.reason) {
+			t.Errorf("%s: skipped = %v, want reason containing %q", name, result.Skipped, tt.reason)
+		}
+	}
+}
+
+func TestFixSourceFStringUse(t *testing.T) {
+	src := "import openai\n\n\ndef version():\n    return f\"v{openai.__version__}\"\n"
+	want := "\n\ndef version():\n    import openai\n    return f\"v{openai.__version__}\"\n"
+	got, result := fixSource(src, violationsFor(src, "import openai"))
+	if len(result.Fixed) != 1 || got != want {
+		t.Errorf("fixSource() = %q (skipped %v), want %q", got, result.Skipped, want)
+	}
+}
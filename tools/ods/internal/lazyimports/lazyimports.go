@@ -1,12 +1,12 @@
 package lazyimports
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -88,6 +88,7 @@ func DefaultLazyImportModules() map[string]LazyImportSettings {
 type ViolationLine struct {
 	LineNum int
 	Content string
+	Module  string // the lazy module the line imports eagerly
 }
 
 // EagerImportResult holds the result of checking a file for eager imports.
@@ -103,45 +104,58 @@ type FileViolation struct {
 	ViolatedModules map[string]struct{}
 }
 
+// Summary aggregates the results of a CheckLazyImports run.
+type Summary struct {
+	FilesScanned        int
+	FilesWithViolations int
+	ViolationLines      int
+	ViolatedModules     map[string]struct{}
+}
+
 // findEagerImports finds eager imports of protected modules in a given file.
+// An import is eager if it runs when the module is imported: at module level,
+// or in a module-level block such as try/if/with or a class body. Imports in
+// functions, under "if TYPE_CHECKING:", or under "if __name__ == "__main__":"
+// are not.
 func findEagerImports(filePath string, patterns []modulePatterns) EagerImportResult {
 	result := EagerImportResult{
 		ViolationLines:  []ViolationLine{},
 		ViolatedModules: make(map[string]struct{}),
 	}
 
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		log.Errorf("Error reading %s: %v", filePath, err)
 		return result
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Debugf("Error closing %s: %v", filePath, err)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		stripped := strings.TrimSpace(line)
+	// Enclosing blocks of the current line, and whether code in each runs
+	// at import time
+	type block struct {
+		indent int
+		eager  bool
+	}
+	var blocks []block
 
-		// Skip comments and empty lines
-		if stripped == "" || strings.HasPrefix(stripped, "#") {
+	lines := lexPython(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	for i, l := range lines {
+		// Skip comments, empty lines, and continuation lines
+		if !l.logical || l.blank() {
 			continue
 		}
+		for len(blocks) > 0 && blocks[len(blocks)-1].indent >= l.indent {
+			blocks = blocks[:len(blocks)-1]
+		}
+		eager := len(blocks) == 0 || blocks[len(blocks)-1].eager
 
-		// Only check imports at module level (indentation == 0)
-		currentIndent := len(line) - len(strings.TrimLeft(line, " \t"))
-		if currentIndent != 0 {
+		stripped := strings.TrimSpace(l.text)
+		if header := logicalCode(lines, i); strings.HasSuffix(header, ":") {
+			blocks = append(blocks, block{indent: l.indent, eager: eager && !lazyBlockPattern.MatchString(stripped)})
 			continue
 		}
 
 		// Quick check: skip lines that don't start with import or from
-		if !strings.HasPrefix(stripped, "import ") && !strings.HasPrefix(stripped, "from ") {
+		if !eager || (!strings.HasPrefix(stripped, "import ") && !strings.HasPrefix(stripped, "from ")) {
 			continue
 		}
 
@@ -151,21 +165,33 @@ func findEagerImports(filePath string, patterns []modulePatterns) EagerImportRes
 				mp.fromPattern.MatchString(stripped) ||
 				mp.fromImportPattern.MatchString(stripped) {
 				result.ViolationLines = append(result.ViolationLines, ViolationLine{
-					LineNum: lineNum,
-					Content: line,
+					LineNum: i + 1,
+					Content: l.text,
+					Module:  mp.moduleName,
 				})
 				result.ViolatedModules[mp.moduleName] = struct{}{}
 			}
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Errorf("Error scanning %s: %v", filePath, err)
-	}
-
 	return result
 }
 
+// lazyBlockPattern matches block headers whose body doesn't run at import
+// time.
+var lazyBlockPattern = regexp.MustCompile(
+	`^(async\s+def|def)\s|^if\s+(typing\.)?TYPE_CHECKING\s*:|^if\s+__name__\s*==\s*['"]__main__['"]\s*:`)
+
+// logicalCode returns the code of the logical line starting at line i, with
+// its continuation lines joined and trailing whitespace removed.
+func logicalCode(lines []sourceLine, i int) string {
+	code := lines[i].code
+	for j := i + 1; j < len(lines) && !lines[j].logical; j++ {
+		code += " " + lines[j].code
+	}
+	return strings.TrimRight(code, " \t")
+}
+
 // isValidPythonFile applies shared filtering rules.
 func isValidPythonFile(filePath string) bool {
 	if !strings.HasSuffix(filePath, ".py") {
@@ -271,11 +297,13 @@ func shouldCheckFileForModule(filePath, backendDir string, settings LazyImportSe
 }
 
 // CheckLazyImports checks that specified modules are only lazily imported.
-// Returns a list of file violations and a set of all violated modules.
-func CheckLazyImports(modulesToLazyImport map[string]LazyImportSettings, providedPaths []string) ([]FileViolation, map[string]struct{}, error) {
+// Returns a list of file violations and a summary of the scan.
+func CheckLazyImports(modulesToLazyImport map[string]LazyImportSettings, providedPaths []string) ([]FileViolation, Summary, error) {
+	summary := Summary{ViolatedModules: make(map[string]struct{})}
+
 	backendDir, err := paths.BackendDir()
 	if err != nil {
-		return nil, nil, err
+		return nil, summary, err
 	}
 
 	log.Infof("Checking for direct imports of lazy modules: %s", formatModuleList(modulesToLazyImport))
@@ -291,21 +319,20 @@ func CheckLazyImports(modulesToLazyImport map[string]LazyImportSettings, provide
 	if len(providedPaths) > 0 {
 		targetFiles, err = collectPythonFiles(providedPaths, backendDir)
 		if err != nil {
-			return nil, nil, err
+			return nil, summary, err
 		}
 		if len(targetFiles) == 0 {
 			log.Info("No matching Python files to check based on provided paths.")
-			return nil, nil, nil
+			return nil, summary, nil
 		}
 	} else {
 		targetFiles, err = FindPythonFiles(backendDir)
 		if err != nil {
-			return nil, nil, err
+			return nil, summary, err
 		}
 	}
 
 	var violations []FileViolation
-	allViolatedModules := make(map[string]struct{})
 
 	// Check each Python file for each module with its specific ignore settings
 	for _, filePath := range targetFiles {
@@ -322,6 +349,7 @@ func CheckLazyImports(modulesToLazyImport map[string]LazyImportSettings, provide
 		}
 
 		result := findEagerImports(filePath, patternsToCheck)
+		summary.FilesScanned++
 
 		if len(result.ViolationLines) > 0 {
 			relPath, err := filepath.Rel(backendDir, filePath)
@@ -335,13 +363,15 @@ func CheckLazyImports(modulesToLazyImport map[string]LazyImportSettings, provide
 				ViolatedModules: result.ViolatedModules,
 			})
 
+			summary.FilesWithViolations++
+			summary.ViolationLines += len(result.ViolationLines)
 			for mod := range result.ViolatedModules {
-				allViolatedModules[mod] = struct{}{}
+				summary.ViolatedModules[mod] = struct{}{}
 			}
 		}
 	}
 
-	return violations, allViolatedModules, nil
+	return violations, summary, nil
 }
 
 // formatModuleList formats the module names for display.
@@ -363,3 +393,43 @@ func FormatViolatedModules(modules map[string]struct{}) string {
 	sort.Strings(names)
 	return strings.Join(names, ", ")
 }
+
+// WatchedFiles returns every checkable Python file under the given paths (or
+// the whole backend when none are given). Files in ignored directories and
+// tests are skipped, as in CheckLazyImports.
+func WatchedFiles(providedPaths []string) ([]string, error) {
+	backendDir, err := paths.BackendDir()
+	if err != nil {
+		return nil, err
+	}
+	startPoints := providedPaths
+	if len(startPoints) == 0 {
+		startPoints = []string{backendDir}
+	}
+	return collectPythonFiles(startPoints, backendDir)
+}
+
+// ModTimes returns the modification time of each of files. Files that can no
+// longer be stat'ed are left out.
+func ModTimes(files []string) map[string]time.Time {
+	mtimes := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			mtimes[f] = info.ModTime()
+		}
+	}
+	return mtimes
+}
+
+// ChangedFiles returns the files in cur that are new or have a different
+// modification time than in prev, sorted by path.
+func ChangedFiles(prev, cur map[string]time.Time) []string {
+	var changed []string
+	for f, mtime := range cur {
+		if old, ok := prev[f]; !ok || !old.Equal(mtime) {
+			changed = append(changed, f)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -2,8 +2,10 @@ package lazyimports
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // Helper function to create a temporary Python file with given content.
@@ -197,6 +199,60 @@ import mygenai  # Should not be flagged
 	}
 }
 
+func TestFindEagerImportsBlockContext(t *testing.T) {
+	// Imports in blocks that run at import time are eager; TYPE_CHECKING and
+	// __main__ blocks are not.
+	testContent := `from typing import TYPE_CHECKING
+import typing
+
+if TYPE_CHECKING:
+    import openai
+    from transformers import AutoTokenizer
+else:
+    import nltk
+
+if typing.TYPE_CHECKING:
+    from openai import OpenAI
+
+try:
+    import tiktoken
+except ImportError:
+    tiktoken = None
+
+class Client:
+    import litellm
+
+    def method(self):
+        import litellm
+
+def helper(
+    x: int,
+) -> None:
+    import markitdown
+
+if __name__ == "__main__":
+    import pypdf
+`
+
+	testPath := createTempPythonFile(t, testContent)
+	defer func() { _ = os.Remove(testPath) }()
+
+	patterns := createPatterns([]string{"openai", "transformers", "nltk", "tiktoken", "litellm", "markitdown", "pypdf"})
+	result := findEagerImports(testPath, patterns)
+
+	lineNumbers := extractLineNumbers(result.ViolationLines)
+	// The else branch, the try block, and the class body
+	want := []int{8, 14, 19}
+	if len(lineNumbers) != len(want) {
+		t.Fatalf("Expected violations on lines %v, got %v", want, lineNumbers)
+	}
+	for _, n := range want {
+		if !containsLineNum(lineNumbers, n) {
+			t.Errorf("Expected line %d in violations, got %v", n, lineNumbers)
+		}
+	}
+}
+
 func TestFindEagerImportsCommentsIgnored(t *testing.T) {
 	// Test that commented imports are ignored.
 	testContent := `
@@ -925,3 +981,82 @@ func TestIgnoreEnvDirectory(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckLazyImportsSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "--quiet", tmpDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	backendDir := filepath.Join(tmpDir, "backend")
+	if err := os.MkdirAll(backendDir, 0755); err != nil {
+		t.Fatalf("Failed to create backend dir: %v", err)
+	}
+	files := map[string]string{
+		"eager.py": "import playwright\nfrom nltk import tokenize\n",
+		"lazy.py":  "def f():\n    import playwright\n",
+		"clean.py": "import os\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(backendDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	t.Chdir(tmpDir)
+
+	modules := map[string]LazyImportSettings{
+		"playwright": NewLazyImportSettings(),
+		"nltk":       NewLazyImportSettings(),
+	}
+	violations, summary, err := CheckLazyImports(modules, nil)
+	if err != nil {
+		t.Fatalf("CheckLazyImports failed: %v", err)
+	}
+
+	if len(violations) != 1 {
+		t.Fatalf("Expected 1 file violation, got %d", len(violations))
+	}
+	if summary.FilesScanned != 3 {
+		t.Errorf("FilesScanned = %d, want 3", summary.FilesScanned)
+	}
+	if summary.FilesWithViolations != 1 {
+		t.Errorf("FilesWithViolations = %d, want 1", summary.FilesWithViolations)
+	}
+	if summary.ViolationLines != 2 {
+		t.Errorf("ViolationLines = %d, want 2", summary.ViolationLines)
+	}
+	if got := FormatViolatedModules(summary.ViolatedModules); got != "nltk, playwright" {
+		t.Errorf("ViolatedModules = %q, want %q", got, "nltk, playwright")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	t1 := time.Unix(2000, 0)
+
+	prev := map[string]time.Time{"a.py": t0, "b.py": t0, "gone.py": t0}
+	cur := map[string]time.Time{"a.py": t0, "b.py": t1, "new.py": t1}
+
+	got := ChangedFiles(prev, cur)
+	want := []string{"b.py", "new.py"}
+	if len(got) != len(want) {
+		t.Fatalf("ChangedFiles = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ChangedFiles[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModTimes_SkipsMissingFiles(t *testing.T) {
+	file := createTempPythonFile(t, "import os\n")
+	missing := filepath.Join(t.TempDir(), "gone.py")
+
+	mtimes := ModTimes([]string{file, missing})
+	if _, ok := mtimes[file]; !ok {
+		t.Errorf("expected a modification time for %s", file)
+	}
+	if _, ok := mtimes[missing]; ok {
+		t.Errorf("expected %s to be left out", missing)
+	}
+}
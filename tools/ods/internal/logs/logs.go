@@ -0,0 +1,461 @@
+// Package logs parses, filters, and formats docker compose log output.
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a single log line from a compose service.
+type Entry struct {
+	Service   string
+	Timestamp time.Time // zero when the line has no timestamp
+	Message   string
+}
+
+// Options controls how Process filters and renders entries.
+type Options struct {
+	// Pattern keeps only entries whose service or message matches. Nil keeps everything.
+	Pattern *regexp.Regexp
+	// Color prefixes each line with a per-service ANSI color.
+	Color bool
+	// LevelColors additionally colors each message by its detected level and
+	// writes a legend first. Ignored unless Color is set.
+	LevelColors bool
+	// Sort buffers all input and orders entries by timestamp before writing.
+	// Leave false when following a live stream.
+	Sort bool
+	// Before and After keep that many entries of context around each Pattern
+	// match, like grep -B/-A. Overlapping windows are merged, and "--"
+	// separates windows that aren't contiguous.
+	Before int
+	After  int
+}
+
+// contextSeparator is written between non-contiguous context windows.
+const contextSeparator = "--"
+
+// servicePalette holds the ANSI color codes assigned to services.
+var servicePalette = []string{"36", "32", "33", "35", "34", "96", "92", "93", "95", "94"}
+
+// Level is the severity detected in a log message.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+// levelPattern matches the level keyword Python and most of our services
+// print near the start of each line.
+var levelPattern = regexp.MustCompile(`\b(DEBUG|INFO|WARN|WARNING|ERROR|CRITICAL|FATAL)\b`)
+
+// DetectLevel returns the level of the first level keyword in message.
+func DetectLevel(message string) Level {
+	switch levelPattern.FindString(message) {
+	case "DEBUG":
+		return LevelDebug
+	case "INFO":
+		return LevelInfo
+	case "WARN", "WARNING":
+		return LevelWarning
+	case "ERROR", "CRITICAL", "FATAL":
+		return LevelError
+	default:
+		return LevelUnknown
+	}
+}
+
+// String returns the level name used in the legend.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarning:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// color returns the ANSI color code for the level, or "" for the default color.
+func (l Level) color() string {
+	switch l {
+	case LevelDebug:
+		return "90"
+	case LevelWarning:
+		return "33"
+	case LevelError:
+		return "31"
+	default:
+		return ""
+	}
+}
+
+// colorize wraps s in the ANSI color code, unless code is empty.
+func colorize(s, code string) string {
+	if code == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Legend returns a one-line key showing each level in its color.
+func Legend() string {
+	parts := []string{"Levels:"}
+	for _, l := range []Level{LevelDebug, LevelInfo, LevelWarning, LevelError} {
+		parts = append(parts, colorize(l.String(), l.color()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseLine parses a line of "docker compose logs --timestamps" output:
+//
+//	api_server-1  | 2025-01-02T03:04:05.123456789Z message
+//
+// Lines without the "service |" prefix are returned with an empty Service and
+// the whole line as the Message. A missing or unparseable timestamp leaves
+// Timestamp zero and keeps the text in Message.
+func ParseLine(line string) Entry {
+	service, rest, ok := strings.Cut(line, "|")
+	if !ok || strings.ContainsAny(strings.TrimSpace(service), " \t") {
+		return Entry{Message: line}
+	}
+
+	e := Entry{Service: strings.TrimSpace(service)}
+	rest = strings.TrimPrefix(rest, " ")
+
+	ts, msg, _ := strings.Cut(rest, " ")
+	if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+		e.Timestamp = t
+		e.Message = msg
+	} else {
+		e.Message = rest
+	}
+	return e
+}
+
+// Match reports whether the entry matches re. A nil re matches everything.
+func (e Entry) Match(re *regexp.Regexp) bool {
+	if re == nil {
+		return true
+	}
+	return re.MatchString(e.Message) || re.MatchString(e.Service)
+}
+
+// Filter returns the entries that match re.
+func Filter(entries []Entry, re *regexp.Regexp) []Entry {
+	if re == nil {
+		return entries
+	}
+	var out []Entry
+	for _, e := range entries {
+		if e.Match(re) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// SortByTimestamp orders entries chronologically. Entries without a timestamp
+// keep their position relative to the entry before them.
+func SortByTimestamp(entries []Entry) {
+	// Carry the previous timestamp forward so untimestamped continuation lines
+	// (e.g. multi-line tracebacks) stay attached to the line they follow.
+	keys := make([]time.Time, len(entries))
+	var last time.Time
+	for i, e := range entries {
+		if !e.Timestamp.IsZero() {
+			last = e.Timestamp
+		}
+		keys[i] = last
+	}
+
+	idx := make([]int, len(entries))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return keys[idx[a]].Before(keys[idx[b]])
+	})
+
+	sorted := make([]Entry, len(entries))
+	for i, j := range idx {
+		sorted[i] = entries[j]
+	}
+	copy(entries, sorted)
+}
+
+// ServiceColor returns the ANSI color code assigned to a service. The same
+// service always gets the same color.
+func ServiceColor(service string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(service))
+	return servicePalette[h.Sum32()%uint32(len(servicePalette))]
+}
+
+// Format renders an entry as "service | timestamp message", padding the
+// service column to width.
+func Format(e Entry, width int, color bool) string {
+	return format(e, width, Options{Color: color})
+}
+
+// format renders an entry per opts, coloring the message by level when
+// opts.LevelColors is set.
+func format(e Entry, width int, opts Options) string {
+	var b strings.Builder
+	if e.Service != "" {
+		label := fmt.Sprintf("%-*s |", width, e.Service)
+		if opts.Color {
+			label = colorize(label, ServiceColor(e.Service))
+		}
+		b.WriteString(label)
+		b.WriteString(" ")
+	}
+	var body string
+	if !e.Timestamp.IsZero() {
+		body = e.Timestamp.Format(time.RFC3339Nano) + " "
+	}
+	body += e.Message
+	if opts.Color && opts.LevelColors {
+		body = colorize(body, DetectLevel(e.Message).color())
+	}
+	b.WriteString(body)
+	return b.String()
+}
+
+// Process reads compose log lines from r, filters them, and writes formatted
+// entries to w. Without opts.Sort, lines are written as they arrive so it can
+// be used on a followed stream.
+func Process(r io.Reader, w io.Writer, opts Options) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if opts.Color && opts.LevelColors {
+		if _, err := fmt.Fprintln(w, Legend()); err != nil {
+			return err
+		}
+	}
+
+	if !opts.Sort {
+		width := 0
+		filter := newContextFilter(opts)
+		for scanner.Scan() {
+			out, sep := filter.add(ParseLine(scanner.Text()))
+			if sep {
+				if _, err := fmt.Fprintln(w, contextSeparator); err != nil {
+					return err
+				}
+			}
+			for _, e := range out {
+				// Widen the service column as new services appear
+				width = max(width, len(e.Service))
+				if _, err := fmt.Fprintln(w, format(e, width, opts)); err != nil {
+					return err
+				}
+			}
+		}
+		return scanner.Err()
+	}
+
+	var entries []Entry
+	for scanner.Scan() {
+		entries = append(entries, ParseLine(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	SortByTimestamp(entries)
+	if opts.Before == 0 && opts.After == 0 {
+		return Write(w, entries, opts)
+	}
+
+	// Collect context windows first so the service column fits all of them
+	filter := newContextFilter(opts)
+	var kept []Entry
+	breaks := map[int]bool{}
+	for _, e := range entries {
+		out, sep := filter.add(e)
+		if sep {
+			breaks[len(kept)] = true
+		}
+		kept = append(kept, out...)
+	}
+	width := 0
+	for _, e := range kept {
+		width = max(width, len(e.Service))
+	}
+	for i, e := range kept {
+		if breaks[i] {
+			if _, err := fmt.Fprintln(w, contextSeparator); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, format(e, width, opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contextFilter keeps entries matching a pattern plus before/after entries of
+// context around each match. It works on a stream, one entry at a time.
+type contextFilter struct {
+	pattern       *regexp.Regexp
+	before, after int
+
+	pending   []Entry // unmatched entries that may become before-context
+	afterLeft int     // after-context entries still to keep
+	emitted   bool    // whether anything has been kept yet
+	skipped   bool    // whether entries were dropped since the last kept one
+}
+
+func newContextFilter(opts Options) *contextFilter {
+	return &contextFilter{pattern: opts.Pattern, before: opts.Before, after: opts.After}
+}
+
+// add returns the entries to keep now that e has been seen, and whether a
+// separator should precede them. Without before/after context no separators
+// are reported, matching plain filtering.
+func (f *contextFilter) add(e Entry) ([]Entry, bool) {
+	if e.Match(f.pattern) {
+		out := append(f.pending, e)
+		f.pending = nil
+		sep := f.emitted && f.skipped && (f.before > 0 || f.after > 0)
+		f.emitted, f.skipped = true, false
+		f.afterLeft = f.after
+		return out, sep
+	}
+	if f.afterLeft > 0 {
+		f.afterLeft--
+		return []Entry{e}, false
+	}
+	if f.before == 0 {
+		f.skipped = true
+		return nil, false
+	}
+	f.pending = append(f.pending, e)
+	if len(f.pending) > f.before {
+		f.pending = f.pending[1:]
+		f.skipped = true
+	}
+	return nil, false
+}
+
+// Write writes the entries matching opts.Pattern to w with a service column
+// wide enough for all of them.
+func Write(w io.Writer, entries []Entry, opts Options) error {
+	entries = Filter(entries, opts.Pattern)
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Service))
+	}
+	for _, e := range entries {
+		if _, err := fmt.Fprintln(w, format(e, width, opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tracebackStart is the first line of a Python traceback.
+const tracebackStart = "Traceback (most recent call last):"
+
+// Traceback is a Python traceback reassembled from consecutive log entries of
+// one service.
+type Traceback struct {
+	Service   string
+	Timestamp time.Time // timestamp of the "Traceback" line; zero if it had none
+	Lines     []string  // message lines, from "Traceback" through the exception
+}
+
+// ReadEntries parses every line of r.
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var entries []Entry
+	for scanner.Scan() {
+		entries = append(entries, ParseLine(scanner.Text()))
+	}
+	return entries, scanner.Err()
+}
+
+// Tracebacks extracts the Python tracebacks from entries, in order. A
+// traceback runs from its "Traceback (most recent call last):" line through the
+// indented frame lines to the first unindented line (the exception). Lines
+// from other services in between are skipped, so merged output still groups,
+// and are still scanned for their own tracebacks.
+func Tracebacks(entries []Entry) []Traceback {
+	var tracebacks []Traceback
+	// used marks entries already claimed by an earlier traceback
+	used := make(map[int]bool)
+	for i := 0; i < len(entries); i++ {
+		if used[i] {
+			continue
+		}
+		start := entries[i]
+		idx := strings.Index(start.Message, tracebackStart)
+		if idx < 0 {
+			continue
+		}
+
+		tb := Traceback{
+			Service:   start.Service,
+			Timestamp: start.Timestamp,
+			Lines:     []string{start.Message[idx:]},
+		}
+		for j := i + 1; j < len(entries); j++ {
+			e := entries[j]
+			if used[j] || e.Service != start.Service {
+				continue
+			}
+			if strings.TrimSpace(e.Message) == "" {
+				continue
+			}
+			used[j] = true
+			tb.Lines = append(tb.Lines, e.Message)
+			if !strings.HasPrefix(e.Message, " ") && !strings.HasPrefix(e.Message, "\t") {
+				// The exception line ends the traceback
+				break
+			}
+		}
+		tracebacks = append(tracebacks, tb)
+	}
+	return tracebacks
+}
+
+// FormatTraceback renders a traceback under a "service @ timestamp" header.
+func FormatTraceback(tb Traceback, color bool) string {
+	header := "---"
+	if tb.Service != "" {
+		label := tb.Service
+		if color {
+			label = colorize(label, ServiceColor(tb.Service))
+		}
+		header += " " + label
+	}
+	if !tb.Timestamp.IsZero() {
+		header += " @ " + tb.Timestamp.Format(time.RFC3339Nano)
+	}
+	header += " ---"
+
+	body := strings.Join(tb.Lines, "\n")
+	if color {
+		body = colorize(body, LevelError.color())
+	}
+	return header + "\n" + body
+}
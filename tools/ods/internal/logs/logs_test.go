@@ -0,0 +1,246 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line        string
+		wantService string
+		wantMessage string
+		wantTS      bool
+	}{
+		{"api_server-1  | 2025-01-02T03:04:05.123456789Z started", "api_server-1", "started", true},
+		{"background-1  | no timestamp here", "background-1", "no timestamp here", false},
+		{"plain line without prefix", "", "plain line without prefix", false},
+		{"a message | with a pipe", "", "a message | with a pipe", false},
+	}
+
+	for _, tt := range tests {
+		e := ParseLine(tt.line)
+		if e.Service != tt.wantService {
+			t.Errorf("ParseLine(%q).Service = %q, want %q", tt.line, e.Service, tt.wantService)
+		}
+		if e.Message != tt.wantMessage {
+			t.Errorf("ParseLine(%q).Message = %q, want %q", tt.line, e.Message, tt.wantMessage)
+		}
+		if e.Timestamp.IsZero() == tt.wantTS {
+			t.Errorf("ParseLine(%q) timestamp parsed = %v, want %v", tt.line, !e.Timestamp.IsZero(), tt.wantTS)
+		}
+	}
+}
+
+func TestProcess_SortsAndFilters(t *testing.T) {
+	input := strings.Join([]string{
+		"api_server-1  | 2025-01-02T03:04:07Z ERROR request failed",
+		"background-1  | 2025-01-02T03:04:05Z INFO task started",
+		"background-1  | 2025-01-02T03:04:06Z ERROR task failed",
+		"api_server-1  | 2025-01-02T03:04:08Z INFO ok",
+	}, "\n")
+
+	var out bytes.Buffer
+	err := Process(strings.NewReader(input), &out, Options{
+		Pattern: regexp.MustCompile("ERROR"),
+		Sort:    true,
+	})
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[0], "background-1 |") || !strings.Contains(lines[0], "task failed") {
+		t.Errorf("expected background error first, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "api_server-1 |") || !strings.Contains(lines[1], "request failed") {
+		t.Errorf("expected api_server error second, got %q", lines[1])
+	}
+}
+
+func TestSortByTimestamp_KeepsContinuationLines(t *testing.T) {
+	entries := []Entry{
+		ParseLine("api_server-1  | 2025-01-02T03:04:07Z Traceback (most recent call last):"),
+		ParseLine("api_server-1  |   File \"app.py\", line 1"),
+		ParseLine("background-1  | 2025-01-02T03:04:05Z earlier"),
+	}
+
+	SortByTimestamp(entries)
+
+	if entries[0].Message != "earlier" {
+		t.Errorf("expected earlier entry first, got %q", entries[0].Message)
+	}
+	if !strings.HasPrefix(entries[2].Message, "  File") {
+		t.Errorf("expected continuation line to follow its traceback, got %q", entries[2].Message)
+	}
+}
+
+func TestFormat_Color(t *testing.T) {
+	e := ParseLine("api_server-1  | hello")
+
+	plain := Format(e, 12, false)
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("expected no ANSI codes without color, got %q", plain)
+	}
+
+	colored := Format(e, 12, true)
+	if !strings.HasPrefix(colored, "\x1b["+ServiceColor("api_server-1")+"m") {
+		t.Errorf("expected service color prefix, got %q", colored)
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := map[string]Level{
+		"DEBUG 03:04:05 loading config":      LevelDebug,
+		"INFO:     127.0.0.1 - GET /health":  LevelInfo,
+		"WARNING  index is stale":            LevelWarning,
+		"ERROR    request failed":            LevelError,
+		"CRITICAL out of memory":             LevelError,
+		"no level here":                      LevelUnknown,
+		"INFORMATION is not a level keyword": LevelUnknown,
+	}
+	for msg, want := range tests {
+		if got := DetectLevel(msg); got != want {
+			t.Errorf("DetectLevel(%q) = %s, want %s", msg, got, want)
+		}
+	}
+}
+
+func TestProcess_LevelColors(t *testing.T) {
+	input := "api_server-1  | 2025-01-02T03:04:07Z ERROR request failed\n"
+
+	var out bytes.Buffer
+	if err := Process(strings.NewReader(input), &out, Options{Color: true, LevelColors: true}); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "Levels:") {
+		t.Fatalf("expected a legend followed by one line, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "\x1b[31m2025-01-02T03:04:07Z ERROR request failed\x1b[0m") {
+		t.Errorf("expected message colored red, got %q", lines[1])
+	}
+
+	out.Reset()
+	if err := Process(strings.NewReader(input), &out, Options{LevelColors: true}); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if strings.Contains(out.String(), "\x1b[") || strings.Contains(out.String(), "Levels:") {
+		t.Errorf("expected no colors or legend without Color, got %q", out.String())
+	}
+}
+
+func TestTracebacks(t *testing.T) {
+	input := strings.Join([]string{
+		"api_server-1  | 2025-01-02T03:04:05Z INFO handling request",
+		"api_server-1  | 2025-01-02T03:04:06Z ERROR Traceback (most recent call last):",
+		"background-1  | 2025-01-02T03:04:06Z INFO unrelated",
+		"api_server-1  | 2025-01-02T03:04:06Z   File \"app.py\", line 1, in <module>",
+		"api_server-1  | 2025-01-02T03:04:06Z     main()",
+		"api_server-1  | 2025-01-02T03:04:06Z ValueError: first",
+		"api_server-1  | 2025-01-02T03:04:07Z INFO recovered",
+		"Traceback (most recent call last):",
+		"  File \"worker.py\", line 2, in run",
+		"KeyError: 'second'",
+	}, "\n")
+
+	entries, err := ReadEntries(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadEntries failed: %v", err)
+	}
+	tbs := Tracebacks(entries)
+	if len(tbs) != 2 {
+		t.Fatalf("expected 2 tracebacks, got %d: %+v", len(tbs), tbs)
+	}
+
+	first := tbs[0]
+	if first.Service != "api_server-1" || first.Timestamp.IsZero() {
+		t.Errorf("expected api_server-1 traceback with a timestamp, got %+v", first)
+	}
+	want := []string{
+		"Traceback (most recent call last):",
+		`  File "app.py", line 1, in <module>`,
+		"    main()",
+		"ValueError: first",
+	}
+	if strings.Join(first.Lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected first traceback lines:\n%s", strings.Join(first.Lines, "\n"))
+	}
+
+	if last := tbs[1]; last.Service != "" || last.Lines[len(last.Lines)-1] != "KeyError: 'second'" {
+		t.Errorf("unexpected second traceback: %+v", last)
+	}
+
+	out := FormatTraceback(first, false)
+	if !strings.HasPrefix(out, "--- api_server-1 @ 2025-01-02T03:04:06Z ---\nTraceback") {
+		t.Errorf("unexpected formatted traceback:\n%s", out)
+	}
+
+	// Tracebacks from two services interleaved in merged output
+	interleaved := strings.Join([]string{
+		"api_server-1  | 2025-01-02T03:04:06Z ERROR Traceback (most recent call last):",
+		"background-1  | 2025-01-02T03:04:06Z ERROR Traceback (most recent call last):",
+		"api_server-1  | 2025-01-02T03:04:06Z   File \"app.py\", line 1, in <module>",
+		"background-1  | 2025-01-02T03:04:06Z   File \"task.py\", line 3, in run",
+		"api_server-1  | 2025-01-02T03:04:06Z ValueError: api",
+		"background-1  | 2025-01-02T03:04:07Z KeyError: 'bg'",
+	}, "\n")
+	entries, err = ReadEntries(strings.NewReader(interleaved))
+	if err != nil {
+		t.Fatalf("ReadEntries failed: %v", err)
+	}
+	tbs = Tracebacks(entries)
+	if len(tbs) != 2 {
+		t.Fatalf("expected 2 interleaved tracebacks, got %d: %+v", len(tbs), tbs)
+	}
+	for i, want := range []struct{ service, last string }{
+		{"api_server-1", "ValueError: api"},
+		{"background-1", "KeyError: 'bg'"},
+	} {
+		tb := tbs[i]
+		if tb.Service != want.service || len(tb.Lines) != 3 || tb.Lines[2] != want.last {
+			t.Errorf("traceback %d: expected %s ending in %q, got %+v", i, want.service, want.last, tb)
+		}
+	}
+}
+
+func TestProcess_Context(t *testing.T) {
+	var lines []string
+	for i, msg := range []string{"a", "b", "MATCH c", "d", "e", "f", "g", "MATCH h", "MATCH i", "j", "k"} {
+		lines = append(lines, fmt.Sprintf("api_server-1  | 2025-01-02T03:04:%02dZ %s", i, msg))
+	}
+	input := strings.Join(lines, "\n")
+
+	for _, sorted := range []bool{false, true} {
+		var out bytes.Buffer
+		err := Process(strings.NewReader(input), &out, Options{
+			Pattern: regexp.MustCompile("MATCH"),
+			Sort:    sorted,
+			Before:  1,
+			After:   1,
+		})
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "--" {
+				got = append(got, line)
+				continue
+			}
+			got = append(got, line[strings.LastIndex(line, " ")+1:])
+		}
+		want := "b c d -- g h i j"
+		if strings.Join(got, " ") != want {
+			t.Errorf("sort=%v: got %q, want %q", sorted, strings.Join(got, " "), want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package output renders tabular command output as an aligned table, JSON, or
+// CSV, so data commands share one --format flag and one implementation.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format selects how a Table is rendered.
+type Format string
+
+const (
+	// FormatTable is a tabwriter-aligned table with a header row.
+	FormatTable Format = "table"
+	// FormatJSON is an array of objects keyed by the column names.
+	FormatJSON Format = "json"
+	// FormatCSV is CSV with a snake_case header row.
+	FormatCSV Format = "csv"
+)
+
+// Formats lists the accepted --format values, for flag help and completion.
+var Formats = []string{string(FormatTable), string(FormatJSON), string(FormatCSV)}
+
+// FlagUsage is the help text for a command's --format flag.
+const FlagUsage = "Output format: table, json, or csv"
+
+// ParseFormat validates a --format value.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatTable, FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid format %q (valid: %s)", s, strings.Join(Formats, ", "))
+	}
+}
+
+// Table is a set of rows with named columns. Column names are written as-is
+// in table headers; CSV headers and JSON keys use them in snake_case
+// (e.g. "TENANT ID" becomes "tenant_id") so scripts get stable field names.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// AddRow appends a row. Missing trailing cells are left empty.
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Write renders t to w in the given format.
+func Write(w io.Writer, format Format, t Table) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, t)
+	case FormatCSV:
+		return writeCSV(w, t)
+	default:
+		return writeTable(w, t)
+	}
+}
+
+// cell returns row[i], or "" if the row is short.
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+func writeTable(w io.Writer, t Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	underline := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		underline[i] = strings.Repeat("-", len(c))
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(t.Columns, "\t"))
+	_, _ = fmt.Fprintln(tw, strings.Join(underline, "\t"))
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			cells[i] = cell(row, i)
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = fieldName(c)
+	}
+	_ = cw.Write(header)
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			cells[i] = cell(row, i)
+		}
+		_ = cw.Write(cells)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// fieldName converts a column name to a snake_case CSV header or JSON key.
+func fieldName(column string) string {
+	return strings.ToLower(strings.Join(strings.Fields(column), "_"))
+}
+
+func writeJSON(w io.Writer, t Table) error {
+	keys := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		keys[i] = fieldName(c)
+	}
+
+	// Build each object by hand so keys keep the column order
+	objects := make([]json.RawMessage, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		var b strings.Builder
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			k, _ := json.Marshal(key)
+			v, _ := json.Marshal(cell(row, i))
+			b.Write(k)
+			b.WriteByte(':')
+			b.Write(v)
+		}
+		b.WriteByte('}')
+		objects = append(objects, json.RawMessage(b.String()))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(objects)
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	table := Table{Columns: []string{"EMAIL", "TENANT ID"}}
+	table.AddRow("a@example.com", "tenant_1")
+	table.AddRow("b,c@example.com")
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatTable, "EMAIL            TENANT ID\n-----            ---------\na@example.com    tenant_1\nb,c@example.com  \n"},
+		{FormatCSV, "email,tenant_id\na@example.com,tenant_1\n\"b,c@example.com\",\n"},
+		{FormatJSON, `[
+  {
+    "email": "a@example.com",
+    "tenant_id": "tenant_1"
+  },
+  {
+    "email": "b,c@example.com",
+    "tenant_id": ""
+  }
+]
+`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, tt.format, table); err != nil {
+			t.Fatalf("Write(%s): %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Write(%s) =\n%q\nwant\n%q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestWrite_EmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, Table{Columns: []string{"EMAIL"}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("got %q, want []", buf.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("JSON"); err != nil || f != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("expected an error for yaml")
+	}
+}
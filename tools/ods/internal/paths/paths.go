@@ -6,18 +6,42 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// gitRootCache memoizes GitRoot per working directory, so the git subprocess
+// runs once per process unless the cwd changes.
+var gitRootCache struct {
+	sync.Mutex
+	cwd  string
+	root string
+}
+
 // GitRoot returns the root directory of the current git repository.
 func GitRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	gitRootCache.Lock()
+	defer gitRootCache.Unlock()
+
+	if gitRootCache.root != "" && gitRootCache.cwd == cwd {
+		return gitRootCache.root, nil
+	}
+
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(string(output)), nil
+
+	gitRootCache.cwd = cwd
+	gitRootCache.root = strings.TrimSpace(string(output))
+	return gitRootCache.root, nil
 }
 
 // DataDir returns the data directory for onyx-dev tools.
@@ -94,6 +118,12 @@ func EnsureSnapshotsDir() error {
 	return os.MkdirAll(SnapshotsDir(), 0755)
 }
 
+// CacheDir returns the directory for ods-managed caches (downloaded
+// artifacts and the like). Everything under it can be safely deleted.
+func CacheDir() string {
+	return filepath.Join(DataDir(), "cache")
+}
+
 // BackendDir returns the backend directory relative to the git root.
 func BackendDir() (string, error) {
 	root, err := GitRoot()
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// metaSuffix is appended to a snapshot's path to name its metadata sidecar.
+const metaSuffix = ".meta.json"
+
+// SnapshotMeta is optional metadata stored next to a snapshot.
+type SnapshotMeta struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Revisions maps an alembic schema ("default", "private") to the
+	// revision the database was at when the snapshot was taken.
+	Revisions map[string]string `json:"revisions,omitempty"`
+}
+
+// MetaPath returns the metadata sidecar path for a snapshot file or directory.
+func MetaPath(snapshotPath string) string {
+	return snapshotPath + metaSuffix
+}
+
+// WriteSnapshotMeta writes the metadata sidecar for a snapshot.
+func WriteSnapshotMeta(snapshotPath string, meta SnapshotMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot metadata: %w", err)
+	}
+	if err := os.WriteFile(MetaPath(snapshotPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshotMeta reads a snapshot's metadata sidecar. It returns nil with
+// no error if the snapshot has none.
+func ReadSnapshotMeta(snapshotPath string) (*SnapshotMeta, error) {
+	data, err := os.ReadFile(MetaPath(snapshotPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot metadata: %w", err)
+	}
+	var meta SnapshotMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot metadata %s: %w", MetaPath(snapshotPath), err)
+	}
+	return &meta, nil
+}
@@ -1,9 +1,14 @@
 package postgres
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 // Config holds PostgreSQL connection configuration.
@@ -49,20 +54,185 @@ func (c *Config) ConnectionString() string {
 		url.QueryEscape(c.User), url.QueryEscape(c.Password), c.Host, c.Port, c.Database)
 }
 
+// Dump formats supported by pg_dump.
+const (
+	FormatCustom    = "custom"
+	FormatPlain     = "plain"
+	FormatDirectory = "directory"
+)
+
+// customDumpMagic is the header every custom-format archive starts with.
+var customDumpMagic = []byte("PGDMP")
+
+// gzipMagic is the header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// NormalizeFormat validates a dump format name, accepting "sql" as an alias
+// for "plain".
+func NormalizeFormat(format string) (string, error) {
+	switch format {
+	case FormatCustom, FormatPlain, FormatDirectory:
+		return format, nil
+	case "sql":
+		return FormatPlain, nil
+	default:
+		return "", fmt.Errorf("unknown dump format %q (expected plain, custom, or directory)", format)
+	}
+}
+
+// FormatExtension returns the file extension used for snapshots in format.
+func FormatExtension(format string) string {
+	switch format {
+	case FormatPlain:
+		return ".sql"
+	case FormatDirectory:
+		return ".dir"
+	default:
+		return ".dump"
+	}
+}
+
+// DetectFormat inspects a snapshot on disk and returns its dump format: a
+// directory is a directory-format archive, a file starting with the PGDMP
+// header is a custom-format archive, and anything else is plain SQL.
+func DetectFormat(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		if _, err := os.Stat(filepath.Join(path, "toc.dat")); err != nil {
+			return "", fmt.Errorf("%s is a directory but not a pg_dump directory archive (no toc.dat)", path)
+		}
+		return FormatDirectory, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, len(customDumpMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		// Too short to be an archive
+		return FormatPlain, nil
+	}
+	if bytes.Equal(header, customDumpMagic) {
+		return FormatCustom, nil
+	}
+	return FormatPlain, nil
+}
+
+// IsGzipped reports whether the file at path is gzip-compressed, judging by
+// its header rather than its extension. Directories are never gzipped.
+func IsGzipped(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() {
+		return false, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, nil
+	}
+	return bytes.Equal(header, gzipMagic), nil
+}
+
+// TOCTables returns the tables listed in "pg_restore -l" output as
+// "schema.table" names, in the order they appear.
+//
+// TOC entries look like:
+//
+//	215; 1259 16386 TABLE public user postgres
+func TOCTables(toc string) []string {
+	var tables []string
+	for _, line := range strings.Split(toc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		_, entry, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(entry)
+		// fields: <catalog oid> <object oid> TABLE <schema> <name> <owner>
+		// ("TABLE DATA" entries repeat the same table and are skipped)
+		if len(fields) < 5 || fields[2] != "TABLE" || fields[3] == "DATA" {
+			continue
+		}
+		tables = append(tables, fields[3]+"."+fields[4])
+	}
+	return tables
+}
+
+// SplitTableName splits "schema.table" into its parts. A bare table name
+// returns an empty schema.
+func SplitTableName(name string) (schema, table string) {
+	if s, t, ok := strings.Cut(name, "."); ok {
+		return s, t
+	}
+	return "", name
+}
+
+// FindTOCTable looks up name (either "table" or "schema.table") among the
+// tables returned by TOCTables and returns the matching entries.
+func FindTOCTable(tables []string, name string) []string {
+	schema, table := SplitTableName(name)
+	var matches []string
+	for _, t := range tables {
+		s, n := SplitTableName(t)
+		if n == table && (schema == "" || s == schema) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
 // PgDumpArgs returns common arguments for pg_dump.
 func (c *Config) PgDumpArgs(format string) []string {
 	args := []string{
 		"-U", c.User,
 		"-d", c.Database,
 	}
-	if format == "custom" {
+	switch format {
+	case FormatCustom:
 		args = append(args, "-Fc")
-	} else {
+	case FormatDirectory:
+		args = append(args, "-Fd")
+	default:
 		args = append(args, "-Fp")
 	}
 	return args
 }
 
+// DefaultCompressLevel is the pg_dump -Z level used for compressed formats, a
+// middle ground between dump speed and size.
+const DefaultCompressLevel = 6
+
+// CompressArgs returns the pg_dump arguments selecting a compression level
+// (0-9) for format. Plain dumps are never compressed so they stay restorable
+// with psql.
+func CompressArgs(format string, level int) ([]string, error) {
+	if level < 0 || level > 9 {
+		return nil, fmt.Errorf("compression level must be between 0 and 9, got %d", level)
+	}
+	if format == FormatPlain {
+		return nil, nil
+	}
+	return []string{"-Z", strconv.Itoa(level)}, nil
+}
+
 // PgRestoreArgs returns common arguments for pg_restore.
 func (c *Config) PgRestoreArgs() []string {
 	return []string{
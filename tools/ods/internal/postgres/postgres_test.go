@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	custom := filepath.Join(dir, "snap.dump")
+	if err := os.WriteFile(custom, []byte("PGDMP\x01\x0e\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Extension doesn't matter; the header does
+	plain := filepath.Join(dir, "snap.dump.sql")
+	if err := os.WriteFile(plain, []byte("-- PostgreSQL database dump\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archiveDir := filepath.Join(dir, "snap.dir")
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(archiveDir, "toc.dat"), []byte("PGDMP"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		custom:     FormatCustom,
+		plain:      FormatPlain,
+		archiveDir: FormatDirectory,
+	}
+	for path, want := range tests {
+		got, err := DetectFormat(path)
+		if err != nil {
+			t.Errorf("DetectFormat(%s) failed: %v", filepath.Base(path), err)
+			continue
+		}
+		if got != want {
+			t.Errorf("DetectFormat(%s) = %q, want %q", filepath.Base(path), got, want)
+		}
+	}
+
+	if _, err := DetectFormat(dir); err == nil {
+		t.Error("expected error for a directory without toc.dat")
+	}
+}
+
+func TestIsGzipped(t *testing.T) {
+	dir := t.TempDir()
+
+	gz := filepath.Join(dir, "snap.sql.gz")
+	if err := os.WriteFile(gz, []byte{0x1f, 0x8b, 0x08, 0x00}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(dir, "snap.gz")
+	if err := os.WriteFile(plain, []byte("-- PostgreSQL database dump\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{gz: true, plain: false, dir: false}
+	for path, want := range tests {
+		got, err := IsGzipped(path)
+		if err != nil {
+			t.Errorf("IsGzipped(%s) failed: %v", filepath.Base(path), err)
+			continue
+		}
+		if got != want {
+			t.Errorf("IsGzipped(%s) = %v, want %v", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestNormalizeFormat(t *testing.T) {
+	if got, err := NormalizeFormat("sql"); err != nil || got != FormatPlain {
+		t.Errorf("NormalizeFormat(sql) = %q, %v; want plain", got, err)
+	}
+	if _, err := NormalizeFormat("tar"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestCompressArgs(t *testing.T) {
+	args, err := CompressArgs(FormatCustom, 9)
+	if err != nil || len(args) != 2 || args[1] != "9" {
+		t.Errorf("CompressArgs(custom, 9) = %v, %v; want [-Z 9]", args, err)
+	}
+	if args, err := CompressArgs(FormatPlain, 6); err != nil || args != nil {
+		t.Errorf("CompressArgs(plain, 6) = %v, %v; want no args", args, err)
+	}
+	if _, err := CompressArgs(FormatDirectory, 10); err == nil {
+		t.Error("expected error for level 10")
+	}
+}
+
+func TestTOCTables(t *testing.T) {
+	toc := `;
+; Archive created at 2025-01-02 03:04:05 UTC
+;     dbname: postgres
+;
+215; 1259 16386 TABLE public user postgres
+216; 1259 16390 TABLE tenant_abc document postgres
+3401; 0 16386 TABLE DATA public user postgres
+3300; 2606 16400 CONSTRAINT public user user_pkey postgres
+`
+	tables := TOCTables(toc)
+	want := []string{"public.user", "tenant_abc.document"}
+	if len(tables) != len(want) {
+		t.Fatalf("TOCTables = %v, want %v", tables, want)
+	}
+	for i := range want {
+		if tables[i] != want[i] {
+			t.Errorf("TOCTables[%d] = %q, want %q", i, tables[i], want[i])
+		}
+	}
+
+	if got := FindTOCTable(tables, "user"); len(got) != 1 || got[0] != "public.user" {
+		t.Errorf("FindTOCTable(user) = %v", got)
+	}
+	if got := FindTOCTable(tables, "public.document"); len(got) != 0 {
+		t.Errorf("FindTOCTable(public.document) = %v, want none", got)
+	}
+}
+
+func TestSnapshotMetaRoundTrip(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "main-20250101_120000.dump")
+
+	meta, err := ReadSnapshotMeta(snapshot)
+	if err != nil || meta != nil {
+		t.Fatalf("ReadSnapshotMeta without sidecar = %v, %v; want nil, nil", meta, err)
+	}
+
+	want := SnapshotMeta{Revisions: map[string]string{"default": "abc123", "private": "def456"}}
+	if err := WriteSnapshotMeta(snapshot, want); err != nil {
+		t.Fatalf("WriteSnapshotMeta: %v", err)
+	}
+	meta, err = ReadSnapshotMeta(snapshot)
+	if err != nil {
+		t.Fatalf("ReadSnapshotMeta: %v", err)
+	}
+	if meta.Revisions["default"] != "abc123" || meta.Revisions["private"] != "def456" {
+		t.Errorf("Revisions = %v, want %v", meta.Revisions, want.Revisions)
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main-20250101_120000.dump", "PGDMP")
+	write("main-20250301_120000.sql.gz", "gz")
+	write("notes.txt", "not a snapshot")
+	write("labeled.sql", "-- dump")
+	labeledAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := WriteSnapshotMeta(filepath.Join(dir, "labeled.sql"), SnapshotMeta{CreatedAt: labeledAt}); err != nil {
+		t.Fatal(err)
+	}
+	archiveDir := filepath.Join(dir, "archive.dir")
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(archiveDir, "toc.dat"), []byte("PGDMP123"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(archiveDir, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := ListSnapshots(dir)
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	var names []string
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	want := []string{"labeled.sql", "main-20250301_120000.sql.gz", "main-20250101_120000.dump", "archive.dir"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("ListSnapshots names = %v, want %v", names, want)
+	}
+	if !snapshots[0].CreatedAt.Equal(labeledAt) || snapshots[0].Meta == nil {
+		t.Errorf("labeled snapshot = %+v, want CreatedAt from metadata", snapshots[0])
+	}
+	if snapshots[3].Size != 8 {
+		t.Errorf("directory snapshot size = %d, want 8", snapshots[3].Size)
+	}
+
+	if s, ok := FindSnapshot(snapshots, "main-20250101_120000"); !ok || s.Name != "main-20250101_120000.dump" {
+		t.Errorf("FindSnapshot without extension = %v, %v", s.Name, ok)
+	}
+	if err := DeleteSnapshot(snapshots[0]); err != nil {
+		t.Fatalf("DeleteSnapshot: %v", err)
+	}
+	for _, path := range []string{snapshots[0].Path, MetaPath(snapshots[0].Path)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after DeleteSnapshot", path)
+		}
+	}
+
+	if snapshots, err := ListSnapshots(filepath.Join(dir, "missing")); err != nil || len(snapshots) != 0 {
+		t.Errorf("ListSnapshots(missing) = %v, %v; want none", snapshots, err)
+	}
+}
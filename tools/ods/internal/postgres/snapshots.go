@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// snapshotExtensions are the extensions of snapshot files and directories,
+// longest first so ".sql.gz" is matched before ".sql".
+var snapshotExtensions = []string{".sql.gz", ".dump", ".sql", ".dir"}
+
+// snapshotTimestamp matches the <branch>-<timestamp> suffix of generated
+// snapshot names.
+var snapshotTimestamp = regexp.MustCompile(`-(\d{8}_\d{6})$`)
+
+// Snapshot is a database snapshot in the snapshots directory.
+type Snapshot struct {
+	// Name is the file or directory name, including its extension.
+	Name string
+	Path string
+	// Size is the file size, or the total size of a directory archive.
+	Size int64
+	// CreatedAt comes from the metadata sidecar, then the timestamp in the
+	// name, then the modification time.
+	CreatedAt time.Time
+	// Meta is the snapshot's metadata sidecar, or nil if it has none.
+	Meta *SnapshotMeta
+}
+
+// SnapshotBaseName returns a snapshot name without its extension, or "" if
+// the name doesn't have a snapshot extension.
+func SnapshotBaseName(name string) string {
+	for _, ext := range snapshotExtensions {
+		if base, ok := strings.CutSuffix(name, ext); ok && base != "" {
+			return base
+		}
+	}
+	return ""
+}
+
+// ListSnapshots returns the snapshots in dir, newest first. A missing
+// directory has no snapshots.
+func ListSnapshots(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		base := SnapshotBaseName(name)
+		if base == "" || entry.IsDir() != strings.HasSuffix(name, ".dir") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat snapshot %s: %w", name, err)
+		}
+
+		path := filepath.Join(dir, name)
+		size := info.Size()
+		if entry.IsDir() {
+			if size, err = dirSize(path); err != nil {
+				return nil, fmt.Errorf("failed to size snapshot %s: %w", name, err)
+			}
+		}
+		meta, err := ReadSnapshotMeta(path)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			Name:      name,
+			Path:      path,
+			Size:      size,
+			CreatedAt: snapshotCreatedAt(base, meta, info.ModTime()),
+			Meta:      meta,
+		})
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// FindSnapshot returns the snapshot whose name, with or without its
+// extension, is name.
+func FindSnapshot(snapshots []Snapshot, name string) (Snapshot, bool) {
+	for _, s := range snapshots {
+		if s.Name == name || SnapshotBaseName(s.Name) == name {
+			return s, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// DeleteSnapshot removes a snapshot and its metadata sidecar.
+func DeleteSnapshot(s Snapshot) error {
+	if err := os.RemoveAll(s.Path); err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %w", s.Name, err)
+	}
+	if err := os.Remove(MetaPath(s.Path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshot metadata for %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// snapshotCreatedAt picks the best available creation time for a snapshot.
+func snapshotCreatedAt(base string, meta *SnapshotMeta, modTime time.Time) time.Time {
+	if meta != nil && !meta.CreatedAt.IsZero() {
+		return meta.CreatedAt
+	}
+	if m := snapshotTimestamp.FindStringSubmatch(base); m != nil {
+		if t, err := time.ParseInLocation("20060102_150405", m[1], time.Local); err == nil {
+			return t
+		}
+	}
+	return modTime
+}
+
+// dirSize returns the total size of the files under a directory.
+func dirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,92 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Object is an S3 object under a listed prefix.
+type Object struct {
+	Key  string // key relative to the listed prefix
+	ETag string // unquoted ETag
+	Size int64
+}
+
+// ListObjects lists every object under an s3:// prefix using the AWS CLI.
+// Keys are returned relative to the prefix.
+func ListObjects(s3url string) ([]Object, error) {
+	parsed, err := ParseS3URL(s3url)
+	if err != nil {
+		return nil, err
+	}
+	prefix := parsed.Key
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	cmd := exec.Command("aws", "s3api", "list-objects-v2",
+		"--bucket", parsed.Bucket,
+		"--prefix", prefix,
+		"--query", "Contents[].{Key: Key, ETag: ETag, Size: Size}",
+		"--output", "json",
+	)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("aws s3api list-objects-v2 failed: %w", err)
+	}
+
+	var raw []struct {
+		Key  string `json:"Key"`
+		ETag string `json:"ETag"`
+		Size int64  `json:"Size"`
+	}
+	// An empty prefix yields "null"
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse object listing: %w", err)
+	}
+
+	objects := make([]Object, 0, len(raw))
+	for _, o := range raw {
+		objects = append(objects, Object{
+			Key:  strings.TrimPrefix(o.Key, prefix),
+			ETag: strings.Trim(o.ETag, `"`),
+			Size: o.Size,
+		})
+	}
+	return objects, nil
+}
+
+// MatchesFile reports whether the local file at path has the same content as
+// the object, using the ETag as an MD5 checksum. Multipart uploads don't have
+// MD5 ETags, so they never match.
+func (o Object) MatchesFile(path string) (bool, error) {
+	if strings.Contains(o.ETag, "-") {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() != o.Size {
+		return false, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false, err
+	}
+	return hex.EncodeToString(h.Sum(nil)) == o.ETag, nil
+}
@@ -0,0 +1,35 @@
+package s3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestObjectMatchesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const helloMD5 = "5d41402abc4b2a76b9719d911017c592"
+
+	tests := []struct {
+		name string
+		obj  Object
+		want bool
+	}{
+		{"same content", Object{ETag: helloMD5, Size: 5}, true},
+		{"different content", Object{ETag: "00000000000000000000000000000000", Size: 5}, false},
+		{"different size", Object{ETag: helloMD5, Size: 6}, false},
+		{"multipart etag", Object{ETag: helloMD5 + "-2", Size: 5}, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.obj.MatchesFile(path)
+		if err != nil {
+			t.Fatalf("%s: MatchesFile failed: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: MatchesFile = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}